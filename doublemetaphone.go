@@ -19,7 +19,6 @@ package twine
 
 import (
 	"bytes"
-	"fmt"
 	"strings"
 )
 
@@ -689,12 +688,13 @@ func (dm *DM) parse() [2]string {
 	return [2]string{p, a}
 }
 
-// DoubleMetaphone ...
+// DoubleMetaphone returns the primary and alternate codes of s, at most
+// codeLength long. An empty s returns ErrEmptyInput.
 func DoubleMetaphone(s string, codeLength int) ([2]string, error) {
 	if len(s) < 1 {
-		return [2]string{}, fmt.Errorf("string length 0")
+		return [2]string{}, ErrEmptyInput
 	}
-	dm := NewDM(s, 4)
+	dm := NewDM(s, codeLength)
 	result := dm.parse()
 	return result, nil
 }
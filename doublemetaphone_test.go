@@ -1,6 +1,9 @@
 package twine
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 var doubleMetaphoneTests = []struct {
 	in  string
@@ -242,6 +245,32 @@ func TestDoubleMetaphone(t *testing.T) {
 	}
 }
 
+func TestDoubleMetaphoneEmpty(t *testing.T) {
+	_, err := DoubleMetaphone("", 4)
+	if !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("DoubleMetaphone() => %v, want %v", err, ErrEmptyInput)
+	}
+}
+
+var codeLengthTests = []struct {
+	in         string
+	codeLength int
+	out        [2]string
+}{
+	{"cambrillo", 2, [2]string{"KM", ""}},
+	{"catherine", 3, [2]string{"K0R", "KTR"}},
+	{"catherine", 0, [2]string{"K0RN", "KTRN"}},
+}
+
+func TestDoubleMetaphoneCodeLength(t *testing.T) {
+	for _, tt := range codeLengthTests {
+		res, _ := DoubleMetaphone(tt.in, tt.codeLength)
+		if res != tt.out {
+			t.Errorf("DoubleMetaphone(%s, %d) => %v, want %v", tt.in, tt.codeLength, res, tt.out)
+		}
+	}
+}
+
 var containsTests = []struct {
 	in    string
 	start int
@@ -0,0 +1,103 @@
+package twine
+
+// EditOpKind is the kind of an edit operation.
+type EditOpKind int
+
+// Edit operations turning a source string into a target.
+const (
+	OpKeep       EditOpKind = iota // the rune is left as is
+	OpInsert                       // a target rune is inserted
+	OpDelete                       // a source rune is deleted
+	OpSubstitute                   // a source rune is replaced by a target rune
+	OpTranspose                    // two adjacent source runes are swapped
+)
+
+var editOpKindNames = [...]string{"keep", "insert", "delete", "substitute", "transpose"}
+
+func (k EditOpKind) String() string {
+	if k < 0 || int(k) >= len(editOpKindNames) {
+		return "unknown"
+	}
+	return editOpKindNames[k]
+}
+
+// EditOp is one step of an edit script. Positions are rune indexes of
+// where the operation applies in the source and target strings.
+type EditOp struct {
+	Kind      EditOpKind
+	SourcePos int
+	TargetPos int
+	Source    string // the source runes consumed, empty for an insertion
+	Target    string // the target runes produced, empty for a deletion
+}
+
+// LevenshteinOps returns the shortest sequence of operations turning
+// source into target, where insertions, deletions, substitutions and
+// transpositions of adjacent runes each count as one edit (see
+// OSADistance). Runes left in place are returned as OpKeep so the script
+// covers both strings from start to end. When several scripts are as
+// short the one keeping, then transposing, then substituting the most
+// runes toward the end of the strings is returned.
+func LevenshteinOps(source string, target string) []EditOp {
+	t1 := []rune(source)
+	t2 := []rune(target)
+
+	// d holds the full optimal string alignment matrix
+	cols := len(t2) + 1
+	d := make([]int, (len(t1)+1)*cols)
+	for i := 0; i <= len(t1); i++ {
+		d[i*cols] = i
+	}
+	for j := 0; j <= len(t2); j++ {
+		d[j] = j
+	}
+	for i := 1; i <= len(t1); i++ {
+		for j := 1; j <= len(t2); j++ {
+			cost := 1
+			if t1[i-1] == t2[j-1] {
+				cost = 0
+			}
+			d[i*cols+j] = levMin(d[(i-1)*cols+j]+1, d[i*cols+j-1]+1, d[(i-1)*cols+j-1]+cost)
+			if transposed(t1, t2, i, j) {
+				d[i*cols+j] = levMin(d[i*cols+j], d[(i-2)*cols+j-2]+1)
+			}
+		}
+	}
+
+	// walk back from the bottom right corner
+	ops := []EditOp{}
+	i, j := len(t1), len(t2)
+	for i > 0 || j > 0 {
+		cur := d[i*cols+j]
+		var op EditOp
+		switch {
+		case i > 0 && j > 0 && t1[i-1] == t2[j-1] && cur == d[(i-1)*cols+j-1]:
+			op = EditOp{OpKeep, i - 1, j - 1, string(t1[i-1]), string(t2[j-1])}
+			i, j = i-1, j-1
+		case transposed(t1, t2, i, j) && cur == d[(i-2)*cols+j-2]+1:
+			op = EditOp{OpTranspose, i - 2, j - 2, string(t1[i-2 : i]), string(t2[j-2 : j])}
+			i, j = i-2, j-2
+		case i > 0 && j > 0 && cur == d[(i-1)*cols+j-1]+1:
+			op = EditOp{OpSubstitute, i - 1, j - 1, string(t1[i-1]), string(t2[j-1])}
+			i, j = i-1, j-1
+		case i > 0 && cur == d[(i-1)*cols+j]+1:
+			op = EditOp{OpDelete, i - 1, j, string(t1[i-1]), ""}
+			i--
+		default:
+			op = EditOp{OpInsert, i, j - 1, "", string(t2[j-1])}
+			j--
+		}
+		ops = append(ops, op)
+	}
+	for l, r := 0, len(ops)-1; l < r; l, r = l+1, r-1 {
+		ops[l], ops[r] = ops[r], ops[l]
+	}
+
+	return ops
+}
+
+// transposed reports whether the two runes of t1 ending at i are the two
+// runes of t2 ending at j swapped.
+func transposed(t1, t2 []rune, i, j int) bool {
+	return i > 1 && j > 1 && t1[i-1] == t2[j-2] && t1[i-2] == t2[j-1] && t1[i-1] != t1[i-2]
+}
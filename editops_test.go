@@ -0,0 +1,108 @@
+package twine
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+var levenshteinOpsTests = []struct {
+	source string
+	target string
+	ops    []EditOp
+}{
+	{"", "", []EditOp{}},
+	{"ab", "ab", []EditOp{
+		{OpKeep, 0, 0, "a", "a"},
+		{OpKeep, 1, 1, "b", "b"},
+	}},
+	{"", "ab", []EditOp{
+		{OpInsert, 0, 0, "", "a"},
+		{OpInsert, 0, 1, "", "b"},
+	}},
+	{"ab", "", []EditOp{
+		{OpDelete, 0, 0, "a", ""},
+		{OpDelete, 1, 0, "b", ""},
+	}},
+	{"teh", "the", []EditOp{
+		{OpKeep, 0, 0, "t", "t"},
+		{OpTranspose, 1, 1, "eh", "he"},
+	}},
+	{"caaat", "cat", []EditOp{
+		{OpKeep, 0, 0, "c", "c"},
+		{OpDelete, 1, 1, "a", ""},
+		{OpDelete, 2, 1, "a", ""},
+		{OpKeep, 3, 1, "a", "a"},
+		{OpKeep, 4, 2, "t", "t"},
+	}},
+	{"kitten", "sitting", []EditOp{
+		{OpSubstitute, 0, 0, "k", "s"},
+		{OpKeep, 1, 1, "i", "i"},
+		{OpKeep, 2, 2, "t", "t"},
+		{OpKeep, 3, 3, "t", "t"},
+		{OpSubstitute, 4, 4, "e", "i"},
+		{OpKeep, 5, 5, "n", "n"},
+		{OpInsert, 6, 6, "", "g"},
+	}},
+	// unicode
+	{"Schüßler", "Schübler", []EditOp{
+		{OpKeep, 0, 0, "S", "S"},
+		{OpKeep, 1, 1, "c", "c"},
+		{OpKeep, 2, 2, "h", "h"},
+		{OpKeep, 3, 3, "ü", "ü"},
+		{OpSubstitute, 4, 4, "ß", "b"},
+		{OpKeep, 5, 5, "l", "l"},
+		{OpKeep, 6, 6, "e", "e"},
+		{OpKeep, 7, 7, "r", "r"},
+	}},
+}
+
+func TestLevenshteinOps(t *testing.T) {
+	for _, tt := range levenshteinOpsTests {
+		res := LevenshteinOps(tt.source, tt.target)
+		if !reflect.DeepEqual(res, tt.ops) {
+			t.Errorf("LevenshteinOps(%s, %s) => %v, want %v", tt.source, tt.target, res, tt.ops)
+		}
+	}
+}
+
+// applyOps rebuilds both strings from an edit script and counts the edits.
+func applyOps(ops []EditOp) (string, string, int) {
+	var source, target string
+	edits := 0
+	for _, op := range ops {
+		source += op.Source
+		target += op.Target
+		if op.Kind != OpKeep {
+			edits++
+		}
+	}
+	return source, target, edits
+}
+
+// TestLevenshteinOpsScript checks on random strings that the script
+// spells out both strings and has as many edits as OSADistance.
+func TestLevenshteinOpsScript(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	word := func() string {
+		r := make([]rune, rnd.Intn(8))
+		for i := range r {
+			r[i] = []rune("abcß")[rnd.Intn(4)]
+		}
+		return string(r)
+	}
+	for i := 0; i < 2000; i++ {
+		a, b := word(), word()
+		ops := LevenshteinOps(a, b)
+		source, target, edits := applyOps(ops)
+		if source != a || target != b || edits != OSADistance(a, b) {
+			t.Fatalf("LevenshteinOps(%s, %s) => %v", a, b, ops)
+		}
+	}
+}
+
+func TestEditOpKindString(t *testing.T) {
+	if OpTranspose.String() != "transpose" || EditOpKind(42).String() != "unknown" {
+		t.Errorf("String() => %s %s", OpTranspose, EditOpKind(42))
+	}
+}
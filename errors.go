@@ -0,0 +1,17 @@
+package twine
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when a key or word is not stored.
+	ErrNotFound = errors.New("not found")
+	// ErrNoSuggestion is returned when a spell checker has no suggestion
+	// for a word.
+	ErrNoSuggestion = errors.New("no suggestion")
+	// ErrEmptyInput is returned when an empty string is given where a
+	// word is required.
+	ErrEmptyInput = errors.New("string length 0")
+	// ErrIndexVersion is returned when reading an index written in an
+	// unsupported format.
+	ErrIndexVersion = errors.New("unsupported index version")
+)
@@ -0,0 +1,177 @@
+package twine
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sync"
+
+	log "github.com/golang/glog"
+)
+
+// DefaultSoftTFIDFThreshold is the inner similarity above which words
+// are treated as the same by SoftTFIDF, as suggested by Cohen et al.
+const DefaultSoftTFIDFThreshold = 0.9
+
+// MongeElkan returns the mean, over the words of source, of the best
+// inner similarity to any word of target, e.g. with JaroWinkler
+// "Jon Smyth" is close to "John A. Smith". It is not symmetric, target
+// words without a match in source are ignored.
+// http://www.aaai.org/Papers/KDD/1996/KDD96-044.pdf
+func MongeElkan(source string, target string, inner func(a, b string) float64) float64 {
+	s1 := tokenWords(source)
+	s2 := tokenWords(target)
+	switch {
+	case len(s1) == 0 && len(s2) == 0:
+		return 1
+	case len(s1) == 0 || len(s2) == 0:
+		return 0
+	}
+
+	total := 0.0
+	for _, w1 := range s1 {
+		best := 0.0
+		for _, w2 := range s2 {
+			best = math.Max(best, inner(w1, w2))
+		}
+		total += best
+	}
+	return total / float64(len(s1))
+}
+
+// mongeElkanJaroWinkler is MongeElkan with JaroWinkler as the inner
+// similarity.
+func mongeElkanJaroWinkler(source string, target string) float64 {
+	return MongeElkan(source, target, JaroWinkler)
+}
+
+// SoftTFIDF scores strings by the TF-IDF weights of their words, words
+// being matched when their inner similarity is above a threshold rather
+// than only when identical. Word weights are learned from a corpus in
+// which each line is a document.
+// https://www.cs.cmu.edu/~wcohen/postscript/kdd-2003-match-ws.pdf
+type SoftTFIDF struct {
+	Documents   int            // lines read from the corpus
+	Frequencies map[string]int // documents containing each word
+
+	inner     func(a, b string) float64
+	threshold float64
+	mu        *sync.Mutex
+}
+
+// NewSoftTFIDF reads a corpus from in the way NewMumboJumbo does,
+// unzipping gzip input. Words are matched by inner, usually JaroWinkler
+// with DefaultSoftTFIDFThreshold, when it is at least threshold.
+func NewSoftTFIDF(in io.Reader, inner func(a, b string) float64, threshold float64) (*SoftTFIDF, error) {
+	s := &SoftTFIDF{
+		Frequencies: map[string]int{},
+		inner:       inner,
+		threshold:   threshold,
+		mu:          &sync.Mutex{},
+	}
+	if err := s.AddReader(in); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// AddReader adds each line of in as a document, unzipping gzip input.
+func (s *SoftTFIDF) AddReader(in io.Reader) error {
+	r, err := corpusReader(in, true)
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		s.AddDocument(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		log.Error(err)
+		return err
+	}
+	return nil
+}
+
+// AddDocument counts the distinct words of doc as one more document.
+func (s *SoftTFIDF) AddDocument(doc string) {
+	words := tokenWords(doc)
+	if len(words) == 0 {
+		return
+	}
+	seen := map[string]struct{}{}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Documents++
+	for _, word := range words {
+		if _, ok := seen[word]; !ok {
+			seen[word] = struct{}{}
+			s.Frequencies[word]++
+		}
+	}
+}
+
+// Similarity returns the soft TF-IDF similarity of source to target
+// between 0 and 1. Each word of source contributes the product of its
+// weight, the weight of the most similar word of target and their inner
+// similarity, when that is at least the threshold. It is not symmetric.
+func (s *SoftTFIDF) Similarity(source string, target string) float64 {
+	s1 := tokenWords(source)
+	s2 := tokenWords(target)
+	switch {
+	case len(s1) == 0 && len(s2) == 0:
+		return 1
+	case len(s1) == 0 || len(s2) == 0:
+		return 0
+	}
+
+	s.mu.Lock()
+	v1 := s.weights(s1)
+	v2 := s.weights(s2)
+	s.mu.Unlock()
+
+	total := 0.0
+	for w1, weight := range v1 {
+		best, match := 0.0, ""
+		for w2 := range v2 {
+			if sim := s.inner(w1, w2); sim > best || (sim == best && w2 < match) {
+				best, match = sim, w2
+			}
+		}
+		if best >= s.threshold {
+			total += weight * v2[match] * best
+		}
+	}
+	return math.Min(total, 1)
+}
+
+// Distance returns 1 - Similarity so a SoftTFIDF can be used as a Metric.
+func (s *SoftTFIDF) Distance(source string, target string) float64 {
+	return 1 - s.Similarity(source, target)
+}
+
+// Normalized marks a SoftTFIDF as a NormalizedMetric.
+func (s *SoftTFIDF) Normalized() {}
+
+// weights returns the unit length TF-IDF vector of words, words missing
+// from the corpus being weighted as the rarest. The caller must hold the
+// lock.
+func (s *SoftTFIDF) weights(words []string) map[string]float64 {
+	tf := map[string]int{}
+	for _, word := range words {
+		tf[word]++
+	}
+
+	v := map[string]float64{}
+	norm := 0.0
+	for word, n := range tf {
+		idf := math.Log(float64(s.Documents+1)/float64(s.Frequencies[word]+1)) + 1
+		v[word] = math.Log(float64(n)+1) * idf
+		norm += v[word] * v[word]
+	}
+	norm = math.Sqrt(norm)
+	for word := range v {
+		v[word] /= norm
+	}
+	return v
+}
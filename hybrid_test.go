@@ -0,0 +1,98 @@
+package twine
+
+import (
+	"bytes"
+	"compress/gzip"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var mongeElkanTests = []struct {
+	source     string
+	target     string
+	similarity float64
+}{
+	{"", "", 1},
+	{"abc", "", 0},
+	{"Jon Smith", "smith john", 0.875},
+	{"john", "john smith", 1},
+	{"john smith", "john", 0.5},
+}
+
+func TestMongeElkan(t *testing.T) {
+	for _, tt := range mongeElkanTests {
+		res := MongeElkan(tt.source, tt.target, LevenshteinSimilarity)
+		if math.Abs(res-tt.similarity) > 1e-9 {
+			t.Errorf("MongeElkan(%s, %s) => %v, want %v", tt.source, tt.target, res, tt.similarity)
+		}
+	}
+}
+
+const softTFIDFCorpus = "john smith\njohn doe\n\njane smith\nwilliam johnson\n"
+
+func TestNewSoftTFIDF(t *testing.T) {
+	s, err := NewSoftTFIDF(strings.NewReader(softTFIDFCorpus), JaroWinkler, DefaultSoftTFIDFThreshold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frequencies := map[string]int{"john": 2, "smith": 2, "doe": 1, "jane": 1, "william": 1, "johnson": 1}
+	if s.Documents != 4 || !reflect.DeepEqual(s.Frequencies, frequencies) {
+		t.Errorf("NewSoftTFIDF() => %d %v, want 4 %v", s.Documents, s.Frequencies, frequencies)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(softTFIDFCorpus))
+	zw.Close()
+	z, err := NewSoftTFIDF(&buf, JaroWinkler, DefaultSoftTFIDFThreshold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if z.Documents != s.Documents || !reflect.DeepEqual(z.Frequencies, s.Frequencies) {
+		t.Errorf("NewSoftTFIDF(gzip) => %d %v, want %d %v", z.Documents, z.Frequencies, s.Documents, s.Frequencies)
+	}
+}
+
+var softTFIDFTests = []struct {
+	source     string
+	target     string
+	similarity float64
+}{
+	{"", "", 1},
+	{"john", "", 0},
+	{"John Smith", "smith, john", 1},
+	{"doe", "smith", 0},
+}
+
+func TestSoftTFIDFSimilarity(t *testing.T) {
+	s, err := NewSoftTFIDF(strings.NewReader(softTFIDFCorpus), JaroWinkler, DefaultSoftTFIDFThreshold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range softTFIDFTests {
+		res := s.Similarity(tt.source, tt.target)
+		if math.Abs(res-tt.similarity) > 1e-9 {
+			t.Errorf("Similarity(%s, %s) => %v, want %v", tt.source, tt.target, res, tt.similarity)
+		}
+	}
+
+	// rare words count for more than common ones
+	if rare, common := s.Similarity("john doe", "jane doe"), s.Similarity("john doe", "john smith"); rare <= common {
+		t.Errorf("Similarity(john doe, jane doe) => %v, want more than %v", rare, common)
+	}
+
+	// close words match as well as identical ones
+	hard, err := NewSoftTFIDF(strings.NewReader(softTFIDFCorpus), JaroWinkler, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	soft := s.Similarity("jon smith", "john smith")
+	if exact := hard.Similarity("jon smith", "john smith"); soft <= exact || soft >= 1 {
+		t.Errorf("Similarity(jon smith, john smith) => %v, want between %v and 1", soft, exact)
+	}
+	if res := s.Distance("jon smith", "john smith"); math.Abs(res-(1-soft)) > 1e-9 {
+		t.Errorf("Distance(jon smith, john smith) => %v, want %v", res, 1-soft)
+	}
+}
@@ -0,0 +1,95 @@
+package twine
+
+// Default Jaro-Winkler parameters from Winkler's paper.
+const (
+	DefaultPrefixScale    = 0.1
+	DefaultBoostThreshold = 0.7
+)
+
+// jaroMaxPrefix is the longest common prefix Jaro-Winkler rewards.
+const jaroMaxPrefix = 4
+
+// Jaro returns the Jaro similarity of two strings between 0 and 1, 1
+// being identical. It counts the runes two strings have in common within
+// half the length of the longer one and how many of those are out of
+// order, which suits short strings such as person names.
+// http://en.wikipedia.org/wiki/Jaro%E2%80%93Winkler_distance
+func Jaro(source string, target string) float64 {
+	if source == target {
+		return 1
+	}
+	t1 := []rune(source)
+	t2 := []rune(target)
+	if len(t1) == 0 || len(t2) == 0 {
+		return 0
+	}
+
+	// runes match if equal and no further apart than window
+	window := levMax(len(t1), len(t2))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	matched1 := make([]bool, len(t1))
+	matched2 := make([]bool, len(t2))
+	matches := 0
+	for i := range t1 {
+		lo := levMax(0, i-window)
+		hi := levMin(len(t2), i+window+1)
+		for j := lo; j < hi; j++ {
+			if !matched2[j] && t1[i] == t2[j] {
+				matched1[i], matched2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	// half the matched runes that are out of order
+	transpositions := 0
+	j := 0
+	for i := range t1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[j] {
+			j++
+		}
+		if t1[i] != t2[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	return (m/float64(len(t1)) + m/float64(len(t2)) + (m-float64(transpositions/2))/m) / 3
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity of two strings with
+// the default prefix scale and boost threshold, see JaroWinklerWith.
+func JaroWinkler(source string, target string) float64 {
+	return JaroWinklerWith(source, target, DefaultPrefixScale, DefaultBoostThreshold)
+}
+
+// JaroWinklerWith returns the Jaro similarity of two strings boosted for
+// a common prefix of up to four runes, each rune of the prefix closing
+// prefixScale of the remaining gap to 1. Only similarities above
+// boostThreshold are boosted. prefixScale should not exceed 0.25 for the
+// result to stay within 0 and 1.
+func JaroWinklerWith(source string, target string, prefixScale, boostThreshold float64) float64 {
+	sim := Jaro(source, target)
+	if sim <= boostThreshold {
+		return sim
+	}
+	prefix := 0
+	t2 := []rune(target)
+	for _, r := range source {
+		if prefix == jaroMaxPrefix || prefix == len(t2) || r != t2[prefix] {
+			break
+		}
+		prefix++
+	}
+	return sim + float64(prefix)*prefixScale*(1-sim)
+}
@@ -0,0 +1,77 @@
+package twine
+
+import (
+	"math"
+	"testing"
+)
+
+var jaroTests = []struct {
+	source      string
+	target      string
+	jaro        float64
+	jaroWinkler float64
+}{
+	{"", "", 1, 1},
+	{"", "abc", 0, 0},
+	{"abc", "", 0, 0},
+	{"abc", "abc", 1, 1},
+	{"abc", "xyz", 0, 0},
+	{"a", "b", 0, 0},
+	{"martha", "marhta", 0.944444, 0.961111},
+	{"dwayne", "duane", 0.822222, 0.840000},
+	{"dixon", "dicksonx", 0.766667, 0.813333},
+	{"jellyfish", "smellyfish", 0.896296, 0.896296},
+	{"richard", "rikhard", 0.904762, 0.923810},
+	{"catherine", "katherine", 0.925926, 0.925926},
+	{"geoff", "jeff", 0.783333, 0.783333},
+	// unicode
+	{"Schüßler", "Schübler", 0.916667, 0.950000},
+}
+
+func TestJaro(t *testing.T) {
+	for _, tt := range jaroTests {
+		res := Jaro(tt.source, tt.target)
+		if math.Abs(res-tt.jaro) > 1e-6 {
+			t.Errorf("Jaro(%s, %s) => %f, want %f", tt.source, tt.target, res, tt.jaro)
+		}
+		if res != Jaro(tt.target, tt.source) {
+			t.Errorf("Jaro(%s, %s) => %f, not symmetric", tt.source, tt.target, res)
+		}
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	for _, tt := range jaroTests {
+		res := JaroWinkler(tt.source, tt.target)
+		if math.Abs(res-tt.jaroWinkler) > 1e-6 {
+			t.Errorf("JaroWinkler(%s, %s) => %f, want %f", tt.source, tt.target, res, tt.jaroWinkler)
+		}
+	}
+}
+
+var jaroWinklerWithTests = []struct {
+	source         string
+	target         string
+	prefixScale    float64
+	boostThreshold float64
+	similarity     float64
+}{
+	{"martha", "marhta", 0.1, 0.7, 0.961111},
+	{"martha", "marhta", 0.25, 0.7, 0.986111},
+	{"martha", "marhta", 0, 0.7, 0.944444},
+	// below the threshold no boost is given
+	{"martha", "marhta", 0.1, 0.95, 0.944444},
+	{"dixon", "dicksonx", 0.1, 0.8, 0.766667},
+	// the prefix is capped at four runes
+	{"abcdefgh", "abcdefgx", 0.1, 0.7, 0.950000},
+}
+
+func TestJaroWinklerWith(t *testing.T) {
+	for _, tt := range jaroWinklerWithTests {
+		res := JaroWinklerWith(tt.source, tt.target, tt.prefixScale, tt.boostThreshold)
+		if math.Abs(res-tt.similarity) > 1e-6 {
+			t.Errorf("JaroWinklerWith(%s, %s, %v, %v) => %f, want %f",
+				tt.source, tt.target, tt.prefixScale, tt.boostThreshold, res, tt.similarity)
+		}
+	}
+}
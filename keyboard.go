@@ -0,0 +1,119 @@
+package twine
+
+import (
+	"math"
+	"unicode"
+)
+
+// KeyboardLayout holds where each character sits on a keyboard so that
+// substituting neighbouring keys, the most likely typing errors, can cost
+// less than substituting distant ones.
+type KeyboardLayout struct {
+	Name string
+	keys map[rune]keyPosition
+}
+
+// adjacentKeyDistance is the furthest apart, in key widths, the centers
+// of two neighbouring keys are on a staggered keyboard.
+const adjacentKeyDistance = 1.5
+
+// keyPosition is the position of a key's center measured in key widths.
+type keyPosition struct {
+	row float64
+	col float64
+}
+
+// Built in keyboard layouts.
+var (
+	QWERTY = NewKeyboardLayout("qwerty", []string{
+		"1234567890-=",
+		"qwertyuiop[]",
+		"asdfghjkl;'",
+		"zxcvbnm,./",
+	}, []float64{0, 0.5, 0.75, 1.25})
+	AZERTY = NewKeyboardLayout("azerty", []string{
+		"&é\"'(-è_çà)=",
+		"azertyuiop^$",
+		"qsdfghjklmù",
+		"wxcvbn,;:!",
+	}, []float64{0, 0.5, 0.75, 1.25})
+	Dvorak = NewKeyboardLayout("dvorak", []string{
+		"1234567890[]",
+		"',.pyfgcrl/=",
+		"aoeuidhtns-",
+		";qjkxbmwvz",
+	}, []float64{0, 0.5, 0.75, 1.25})
+	// PhoneKeypad places letters on the digit keys they are typed with,
+	// letters sharing a key are the closest.
+	PhoneKeypad = NewKeyboardLayout("phone", []string{
+		"1", "2abc", "3def",
+		"4ghi", "5jkl", "6mno",
+		"7pqrs", "8tuv", "9wxyz",
+		"*", "0 ", "#",
+	}, nil)
+)
+
+// NewKeyboardLayout creates a layout from rows of keys, each row shifted
+// right by the matching offset in key widths to account for staggering.
+// Without offsets rows are read as a grid of three keys per row where
+// every character of a row string sits on the same key, as on a phone.
+func NewKeyboardLayout(name string, rows []string, offsets []float64) *KeyboardLayout {
+	k := &KeyboardLayout{Name: name, keys: map[rune]keyPosition{}}
+	if offsets == nil {
+		for i, key := range rows {
+			for _, r := range key {
+				k.keys[r] = keyPosition{float64(i / 3), float64(i % 3)}
+			}
+		}
+		return k
+	}
+	for i, row := range rows {
+		col := 0
+		for _, r := range row {
+			k.keys[r] = keyPosition{float64(i), offsets[i] + float64(col)}
+			col++
+		}
+	}
+	return k
+}
+
+// distance returns the distance in key widths between the keys typing a
+// and b, or false if either is not on the keyboard.
+func (k *KeyboardLayout) distance(a, b rune) (float64, bool) {
+	pa, ok := k.keys[unicode.ToLower(a)]
+	if !ok {
+		return 0, false
+	}
+	pb, ok := k.keys[unicode.ToLower(b)]
+	if !ok {
+		return 0, false
+	}
+	return math.Hypot(pa.row-pb.row, pa.col-pb.col), true
+}
+
+// SubstitutionCost returns the cost of typing b instead of a. Keys next
+// to each other, including diagonally, cost 0.5 and characters sharing a
+// key cost 0.25. Anything else, including characters missing from the
+// layout, costs 1.
+func (k *KeyboardLayout) SubstitutionCost(a, b rune) float64 {
+	if a == b {
+		return 0
+	}
+	d, ok := k.distance(a, b)
+	switch {
+	case !ok:
+		return 1
+	case d == 0:
+		return 0.25
+	case d <= adjacentKeyDistance:
+		return 0.5
+	default:
+		return 1
+	}
+}
+
+// CostModel returns a cost model for WeightedLevenshtein charging 1 for
+// insertions and deletions and SubstitutionCost for substitutions.
+func (k *KeyboardLayout) CostModel() CostModel {
+	return CostModel{Insert: 1, Delete: 1, SubstituteFunc: k.SubstitutionCost}
+}
@@ -0,0 +1,48 @@
+package twine
+
+import (
+	"math"
+	"testing"
+)
+
+var substitutionCostTests = []struct {
+	layout *KeyboardLayout
+	a      rune
+	b      rune
+	cost   float64
+}{
+	{QWERTY, 'a', 'a', 0},
+	{QWERTY, 's', 'a', 0.5},
+	{QWERTY, 's', 'w', 0.5},
+	{QWERTY, 's', 'x', 0.5},
+	{QWERTY, 'S', 'a', 0.5},
+	{QWERTY, 's', 'u', 1},
+	{QWERTY, 'q', 'p', 1},
+	{QWERTY, 's', 'é', 1},
+	{AZERTY, 'a', 'z', 0.5},
+	{AZERTY, 'a', 'q', 0.5},
+	{AZERTY, 'a', 's', 1},
+	{Dvorak, 'a', 'o', 0.5},
+	{Dvorak, 'a', 's', 1},
+	{PhoneKeypad, 'a', 'c', 0.25},
+	{PhoneKeypad, 'a', 'd', 0.5},
+	{PhoneKeypad, 'a', 'z', 1},
+}
+
+func TestSubstitutionCost(t *testing.T) {
+	for _, tt := range substitutionCostTests {
+		res := tt.layout.SubstitutionCost(tt.a, tt.b)
+		if math.Abs(res-tt.cost) > 1e-9 {
+			t.Errorf("%s.SubstitutionCost(%c, %c) => %v, want %v", tt.layout.Name, tt.a, tt.b, res, tt.cost)
+		}
+	}
+}
+
+func TestKeyboardCostModel(t *testing.T) {
+	costs := QWERTY.CostModel()
+	cat := WeightedLevenshtein("cst", "cat", costs)
+	cut := WeightedLevenshtein("cst", "cut", costs)
+	if cat >= cut {
+		t.Errorf("WeightedLevenshtein(cst, cat) => %v, want less than cut %v", cat, cut)
+	}
+}
@@ -0,0 +1,91 @@
+package twine
+
+// LCSLength returns the length in runes of the longest common
+// subsequence of two strings, the runes both contain in the same order
+// though not necessarily next to each other.
+// http://en.wikipedia.org/wiki/Longest_common_subsequence_problem
+func LCSLength(source string, target string) int {
+	l := levenshteinPool.Get().(*Levenshtein)
+	defer levenshteinPool.Put(l)
+	t1, t2 := l.runes(source, target)
+
+	// v0 is the previous row, v1 the current one
+	v0, v1 := l.rows(len(t2) + 1)
+	for i := 0; i < len(t1); i++ {
+		for j := 0; j < len(t2); j++ {
+			if t1[i] == t2[j] {
+				v1[j+1] = v0[j] + 1
+			} else {
+				v1[j+1] = levMax(v1[j], v0[j+1])
+			}
+		}
+		v0, v1 = v1, v0
+	}
+
+	return v0[len(t2)]
+}
+
+// LCS returns the longest common subsequence of two strings. When there
+// are several, runes of source are skipped before runes of target to
+// choose between them.
+func LCS(source string, target string) string {
+	t1 := []rune(source)
+	t2 := []rune(target)
+
+	// d[i][j] is the length of the LCS of t1[i:] and t2[j:]
+	cols := len(t2) + 1
+	d := make([]int, (len(t1)+1)*cols)
+	for i := len(t1) - 1; i >= 0; i-- {
+		for j := len(t2) - 1; j >= 0; j-- {
+			if t1[i] == t2[j] {
+				d[i*cols+j] = d[(i+1)*cols+j+1] + 1
+			} else {
+				d[i*cols+j] = levMax(d[(i+1)*cols+j], d[i*cols+j+1])
+			}
+		}
+	}
+
+	lcs := make([]rune, 0, d[0])
+	for i, j := 0, 0; i < len(t1) && j < len(t2); {
+		switch {
+		case t1[i] == t2[j]:
+			lcs = append(lcs, t1[i])
+			i, j = i+1, j+1
+		case d[(i+1)*cols+j] >= d[i*cols+j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	return string(lcs)
+}
+
+// LongestCommonSubstring returns the longest run of consecutive runes
+// found in both strings. When there are several the one ending first in
+// source is returned.
+// http://en.wikipedia.org/wiki/Longest_common_substring_problem
+func LongestCommonSubstring(source string, target string) string {
+	l := levenshteinPool.Get().(*Levenshtein)
+	defer levenshteinPool.Put(l)
+	t1, t2 := l.runes(source, target)
+
+	// v1[j+1] is the length of the common suffix of t1[:i+1] and t2[:j+1]
+	v0, v1 := l.rows(len(t2) + 1)
+	longest, end := 0, 0
+	for i := 0; i < len(t1); i++ {
+		for j := 0; j < len(t2); j++ {
+			if t1[i] != t2[j] {
+				v1[j+1] = 0
+				continue
+			}
+			v1[j+1] = v0[j] + 1
+			if v1[j+1] > longest {
+				longest, end = v1[j+1], i+1
+			}
+		}
+		v0, v1 = v1, v0
+	}
+
+	return string(t1[end-longest : end])
+}
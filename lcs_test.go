@@ -0,0 +1,91 @@
+package twine
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+var lcsTests = []struct {
+	source    string
+	target    string
+	lcs       string
+	substring string
+}{
+	{"", "", "", ""},
+	{"abc", "", "", ""},
+	{"", "abc", "", ""},
+	{"abc", "abc", "abc", "abc"},
+	{"abc", "xyz", "", ""},
+	{"abcbdab", "bdcaba", "bdab", "ab"},
+	{"AGGTAB", "GXTXAYB", "GTAB", "A"},
+	{"interoperability", "interpretability", "interprability", "ability"},
+	{"xabcyabcdz", "abcd", "abcd", "abcd"},
+	{"records--of", "records of", "recordsof", "records"},
+	// unicode
+	{"Schüßler", "Schübler", "Schüler", "Schü"},
+	{"français", "francais", "franais", "fran"},
+}
+
+func TestLCS(t *testing.T) {
+	for _, tt := range lcsTests {
+		res := LCS(tt.source, tt.target)
+		if res != tt.lcs {
+			t.Errorf("LCS(%s, %s) => %s, want %s", tt.source, tt.target, res, tt.lcs)
+		}
+	}
+}
+
+func TestLCSLength(t *testing.T) {
+	for _, tt := range lcsTests {
+		res := LCSLength(tt.source, tt.target)
+		if res != len([]rune(tt.lcs)) {
+			t.Errorf("LCSLength(%s, %s) => %d, want %d", tt.source, tt.target, res, len([]rune(tt.lcs)))
+		}
+	}
+}
+
+func TestLongestCommonSubstring(t *testing.T) {
+	for _, tt := range lcsTests {
+		res := LongestCommonSubstring(tt.source, tt.target)
+		if res != tt.substring {
+			t.Errorf("LongestCommonSubstring(%s, %s) => %s, want %s", tt.source, tt.target, res, tt.substring)
+		}
+	}
+}
+
+// isSubsequence reports whether sub can be made by deleting runes of s.
+func isSubsequence(sub, s string) bool {
+	r := []rune(sub)
+	i := 0
+	for _, c := range s {
+		if i < len(r) && r[i] == c {
+			i++
+		}
+	}
+	return i == len(r)
+}
+
+// TestLCSRandom checks on random strings that LCS is a common subsequence
+// as long as LCSLength and that the common substring is in both strings.
+func TestLCSRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	word := func() string {
+		r := make([]rune, rnd.Intn(10))
+		for i := range r {
+			r[i] = []rune("abcß")[rnd.Intn(4)]
+		}
+		return string(r)
+	}
+	for i := 0; i < 2000; i++ {
+		a, b := word(), word()
+		lcs := LCS(a, b)
+		if !isSubsequence(lcs, a) || !isSubsequence(lcs, b) || len([]rune(lcs)) != LCSLength(a, b) {
+			t.Fatalf("LCS(%s, %s) => %s, length %d", a, b, lcs, LCSLength(a, b))
+		}
+		sub := LongestCommonSubstring(a, b)
+		if !strings.Contains(a, sub) || !strings.Contains(b, sub) || len([]rune(sub)) > len([]rune(lcs)) {
+			t.Fatalf("LongestCommonSubstring(%s, %s) => %s", a, b, sub)
+		}
+	}
+}
@@ -1,5 +1,13 @@
 package twine
 
+import (
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
 // levMin returns the minimum of the given variadic input.
 // if no input is given a -1 is returned.
 func levMin(args ...int) int {
@@ -18,25 +26,163 @@ func levMin(args ...int) int {
 	}
 }
 
+// Levenshtein computes Levenshtein distances reusing its buffers between
+// calls so that computing a distance doesn't allocate. Strings made of
+// ASCII characters are compared byte by byte without converting them to
+// runes. A Levenshtein is not safe for concurrent use, use one per
+// goroutine or share them through a sync.Pool.
+type Levenshtein struct {
+	r1, r2   []rune
+	v0, v1   []int
+	peq      map[rune]uint64
+	clusters map[string]rune
+}
+
+// NewLevenshtein creates a Levenshtein distance calculator.
+func NewLevenshtein() *Levenshtein {
+	return &Levenshtein{peq: map[rune]uint64{}, clusters: map[string]rune{}}
+}
+
+// levenshteinPool holds the calculators used by the package level
+// distance functions.
+var levenshteinPool = sync.Pool{
+	New: func() interface{} {
+		return NewLevenshtein()
+	},
+}
+
 // LevenshteinDistance measures the distance between two strings.
 // http://en.wikipedia.org/wiki/Levenshtein_distance
+// When the shorter string is at most 64 runes the distance is computed
+// with Myers' bit-vector algorithm, processing a column of the distance
+// matrix per machine word operation.
+// http://www.gersteinlab.org/courses/452/09-spring/pdf/Myers.pdf
 func LevenshteinDistance(source string, target string) int {
+	l := levenshteinPool.Get().(*Levenshtein)
+	d := l.Distance(source, target)
+	levenshteinPool.Put(l)
+	return d
+}
+
+// Distance measures the Levenshtein distance between two strings, see
+// LevenshteinDistance.
+func (l *Levenshtein) Distance(source string, target string) int {
 	// degenerate cases
 	if source == target {
 		return 0
 	}
-	if len(source) == 0 {
-		return len(target)
+	if isASCII(source) && isASCII(target) &&
+		(len(source) <= 64 || len(target) <= 64) {
+		if len(source) == 0 || len(target) == 0 {
+			return len(source) + len(target)
+		}
+		return myersASCII(source, target)
+	}
+	return l.distance(l.runes(source, target))
+}
+
+// GraphemeDistance measures the Levenshtein distance between two strings
+// counting extended grapheme clusters rather than runes, so an emoji
+// with a skin tone modifier or a letter followed by combining accents is
+// a single character.
+func (l *Levenshtein) GraphemeDistance(source string, target string) int {
+	if source == target {
+		return 0
 	}
-	if len(target) == 0 {
-		return len(source)
+	return l.distance(l.graphemes(source, target))
+}
+
+// distance measures the Levenshtein distance between two rune slices.
+func (l *Levenshtein) distance(t1, t2 []rune) int {
+	if len(t1) == 0 || len(t2) == 0 {
+		return len(t1) + len(t2)
 	}
-	t1 := []rune(source)
-	t2 := []rune(target)
+	if len(t1) <= 64 || len(t2) <= 64 {
+		return l.myers(t1, t2)
+	}
+	return l.twoRow(t1, t2)
+}
+
+// runes converts source and target to runes in the reusable buffers.
+func (l *Levenshtein) runes(source, target string) ([]rune, []rune) {
+	l.r1, l.r2 = l.r1[:0], l.r2[:0]
+	for _, r := range source {
+		l.r1 = append(l.r1, r)
+	}
+	for _, r := range target {
+		l.r2 = append(l.r2, r)
+	}
+	return l.r1, l.r2
+}
+
+// graphemes splits source and target into extended grapheme clusters in
+// the reusable buffers. Clusters of a single rune are kept as is, longer
+// ones are numbered past unicode.MaxRune so that equal clusters compare
+// equal.
+func (l *Levenshtein) graphemes(source, target string) ([]rune, []rune) {
+	if l.clusters == nil {
+		l.clusters = map[string]rune{}
+	}
+	for cluster := range l.clusters {
+		delete(l.clusters, cluster)
+	}
+	split := func(s string, buf []rune) []rune {
+		state := -1
+		for len(s) > 0 {
+			var cluster string
+			cluster, s, _, state = uniseg.FirstGraphemeClusterInString(s, state)
+			r, size := utf8.DecodeRuneInString(cluster)
+			if size < len(cluster) {
+				id, ok := l.clusters[cluster]
+				if !ok {
+					id = unicode.MaxRune + 1 + rune(len(l.clusters))
+					l.clusters[cluster] = id
+				}
+				r = id
+			}
+			buf = append(buf, r)
+		}
+		return buf
+	}
+	l.r1 = split(source, l.r1[:0])
+	l.r2 = split(target, l.r2[:0])
+	return l.r1, l.r2
+}
+
+// rows returns the two work rows resized to n and set to zero.
+func (l *Levenshtein) rows(n int) ([]int, []int) {
+	if cap(l.v0) < n {
+		l.v0 = make([]int, n)
+		l.v1 = make([]int, n)
+	}
+	l.v0, l.v1 = l.v0[:n], l.v1[:n]
+	for i := range l.v0 {
+		l.v0[i], l.v1[i] = 0, 0
+	}
+	return l.v0, l.v1
+}
+
+// isASCII reports whether s only holds ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
 
+// levenshteinTwoRow computes the Levenshtein distance keeping two rows
+// of the distance matrix.
+func levenshteinTwoRow(t1, t2 []rune) int {
+	return (&Levenshtein{}).twoRow(t1, t2)
+}
+
+// twoRow computes the Levenshtein distance keeping two rows of the
+// distance matrix.
+func (l *Levenshtein) twoRow(t1, t2 []rune) int {
 	// create two work vectors of integer distances
-	v0 := make([]int, len(t2)+1)
-	v1 := make([]int, len(t2)+1)
+	v0, v1 := l.rows(len(t2) + 1)
 
 	// initialize v0 (the previous row of distances)
 	// this row is A[0][i]: edit distance for an empty s
@@ -53,6 +199,274 @@ func LevenshteinDistance(source string, target string) int {
 
 		// use formula to fill in the rest of the row
 		var cost int
+		for j := 0; j < len(t2); j++ {
+			if t1[i] == t2[j] {
+				cost = 0
+			} else {
+				cost = 1
+			}
+			d := v0[j] + cost
+			if v1[j]+1 < d {
+				d = v1[j] + 1
+			}
+			if v0[j+1]+1 < d {
+				d = v0[j+1] + 1
+			}
+			v1[j+1] = d
+		}
+
+		// v1 (current row) becomes v0 (previous row) for next iteration
+		v0, v1 = v1, v0
+	}
+
+	return v0[len(t2)]
+}
+
+// myers computes the Levenshtein distance of two non empty strings, one
+// of which is at most 64 runes, with Hyyrö's formulation of Myers'
+// algorithm.
+func (l *Levenshtein) myers(t1, t2 []rune) int {
+	// the pattern is the shorter string, its runes are the bits of a word
+	pattern, text := t1, t2
+	if len(pattern) > len(text) {
+		pattern, text = text, pattern
+	}
+
+	// peq holds for each rune the positions it occurs at in pattern,
+	// ascii runes are looked up in an array instead of the map
+	var ascii [utf8.RuneSelf]uint64
+	if l.peq == nil {
+		l.peq = map[rune]uint64{}
+	}
+	peq := l.peq
+	for i, r := range pattern {
+		if r < utf8.RuneSelf {
+			ascii[r] |= 1 << uint(i)
+		} else {
+			peq[r] |= 1 << uint(i)
+		}
+	}
+
+	var bits myersBits
+	bits.init(len(pattern))
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			bits.step(ascii[r])
+		} else {
+			bits.step(peq[r])
+		}
+	}
+
+	for _, r := range pattern {
+		delete(peq, r)
+	}
+	return bits.score
+}
+
+// myersASCII computes the Levenshtein distance of two non empty ASCII
+// strings, one of which is at most 64 bytes long.
+func myersASCII(pattern, text string) int {
+	if len(pattern) > len(text) {
+		pattern, text = text, pattern
+	}
+	var peq [utf8.RuneSelf]uint64
+	for i := 0; i < len(pattern); i++ {
+		peq[pattern[i]] |= 1 << uint(i)
+	}
+	var bits myersBits
+	bits.init(len(pattern))
+	for i := 0; i < len(text); i++ {
+		bits.step(peq[text[i]])
+	}
+	return bits.score
+}
+
+// myersBits holds the vertical deltas of the last computed column of the
+// distance matrix as bit vectors and the distance in its last row.
+type myersBits struct {
+	pv, mv uint64
+	last   uint64
+	score  int
+}
+
+// init prepares the first column for a pattern of length m.
+func (b *myersBits) init(m int) {
+	b.pv, b.mv = ^uint64(0), 0
+	b.last = uint64(1) << uint(m-1)
+	b.score = m
+}
+
+// step computes the next column given the positions in the pattern
+// matching the next character of the text.
+func (b *myersBits) step(eq uint64) {
+	xv := eq | b.mv
+	xh := (((eq & b.pv) + b.pv) ^ b.pv) | eq
+	ph := b.mv | ^(xh | b.pv)
+	mh := b.pv & xh
+	if ph&b.last != 0 {
+		b.score++
+	}
+	if mh&b.last != 0 {
+		b.score--
+	}
+	// the first row of the matrix grows by one per column
+	ph = ph<<1 | 1
+	mh <<= 1
+	b.pv = mh | ^(xv | ph)
+	b.mv = ph & xv
+}
+
+// LevenshteinSimilarity returns the Levenshtein distance between two
+// strings normalized to a similarity between 0 and 1, 1 - d/max(|a|, |b|)
+// with lengths in runes. Identical strings, including two empty ones,
+// have a similarity of 1 and strings without a rune in common 0.
+func LevenshteinSimilarity(source string, target string) float64 {
+	return similarity(LevenshteinDistance(source, target),
+		utf8.RuneCountInString(source), utf8.RuneCountInString(target))
+}
+
+// similarity normalizes a distance d between strings of n and m runes,
+// distances larger than the longer string are treated as its length.
+func similarity(d, n, m int) float64 {
+	if n == 0 && m == 0 {
+		return 1
+	}
+	return 1 - float64(levMin(d, levMax(n, m)))/float64(levMax(n, m))
+}
+
+// YujianBoSimilarity returns the Levenshtein distance between two strings
+// normalized with both of their lengths, 1 - 2d/(|a|+|b|+d). Unlike
+// LevenshteinSimilarity it accounts for the length of the shorter string,
+// e.g. "abcd" is as dissimilar to "" as to "wxyz" for
+// LevenshteinSimilarity but closer to "wxyz" here, and the normalized
+// distance is a metric.
+// http://dx.doi.org/10.1109/TPAMI.2007.1078
+func YujianBoSimilarity(source string, target string) float64 {
+	d := LevenshteinDistance(source, target)
+	if d == 0 {
+		return 1
+	}
+	n, m := utf8.RuneCountInString(source), utf8.RuneCountInString(target)
+	return 1 - 2*float64(d)/float64(n+m+d)
+}
+
+// LevenshteinWithin reports whether the Levenshtein distance between two
+// strings is at most k, returning the distance if it is and k+1 if not.
+// Only the diagonal band of width 2k+1 that can hold a distance of at
+// most k is computed (Ukkonen) and computation stops as soon as every
+// cell of a row exceeds k, so it is much cheaper than
+// LevenshteinDistance for small k.
+func LevenshteinWithin(source string, target string, k int) (int, bool) {
+	l := levenshteinPool.Get().(*Levenshtein)
+	d, ok := l.Within(source, target, k)
+	levenshteinPool.Put(l)
+	return d, ok
+}
+
+// Within reports whether the Levenshtein distance between two strings
+// is at most k, see LevenshteinWithin.
+func (l *Levenshtein) Within(source string, target string, k int) (int, bool) {
+	if k < 0 {
+		return 0, false
+	}
+	if source == target {
+		return 0, true
+	}
+	t1, t2 := l.runes(source, target)
+	if abs(len(t1)-len(t2)) > k {
+		return k + 1, false
+	}
+	if len(t1) == 0 || len(t2) == 0 {
+		return len(t1) + len(t2), true
+	}
+
+	// cells outside of the band are treated as k+1
+	over := k + 1
+	v0, v1 := l.rows(len(t2) + 1)
+	for j := 0; j < len(v0); j++ {
+		v0[j] = levMin(j, over)
+	}
+
+	for i := 0; i < len(t1); i++ {
+		// the band of row i+1 spans columns lo to hi
+		lo := levMin(levMax(1, i+1-k), len(t2)+1)
+		hi := levMin(len(t2), i+1+k)
+		if lo > 1 {
+			v1[lo-1] = over
+		} else {
+			v1[0] = levMin(i+1, over)
+		}
+		rowMin := v1[lo-1]
+		for j := lo; j <= hi; j++ {
+			cost := 1
+			if t1[i] == t2[j-1] {
+				cost = 0
+			}
+			v1[j] = levMin(v1[j-1]+1, v0[j]+1, v0[j-1]+cost, over)
+			if v1[j] < rowMin {
+				rowMin = v1[j]
+			}
+		}
+		if hi < len(t2) {
+			v1[hi+1] = over
+		}
+		if rowMin > k {
+			return over, false
+		}
+		v0, v1 = v1, v0
+	}
+
+	d := v0[len(t2)]
+	return d, d <= k
+}
+
+// levMax returns the maximum of a and b.
+func levMax(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// abs returns the absolute value of x.
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// OSADistance measures the optimal string alignment distance between two
+// strings, the Levenshtein distance where swapping two adjacent
+// characters counts as a single edit. A substring can't be edited more
+// than once so it doesn't satisfy the triangle inequality, e.g.
+// OSADistance("ca", "abc") is 3.
+// http://en.wikipedia.org/wiki/Damerau%E2%80%93Levenshtein_distance
+func OSADistance(source string, target string) int {
+	// degenerate cases
+	if source == target {
+		return 0
+	}
+	t1 := []rune(source)
+	t2 := []rune(target)
+	if len(t1) == 0 {
+		return len(t2)
+	}
+	if len(t2) == 0 {
+		return len(t1)
+	}
+
+	// three rows, v2 being the one before the previous row v0
+	v2 := make([]int, len(t2)+1)
+	v0 := make([]int, len(t2)+1)
+	v1 := make([]int, len(t2)+1)
+	for i := 0; i < len(v0); i++ {
+		v0[i] = i
+	}
+
+	for i := 0; i < len(t1); i++ {
+		v1[0] = i + 1
+		var cost int
 		for j := 0; j < len(t2); j++ {
 			if t1[i] == t2[j] {
 				cost = 0
@@ -60,13 +474,73 @@ func LevenshteinDistance(source string, target string) int {
 				cost = 1
 			}
 			v1[j+1] = levMin(v1[j]+1, v0[j+1]+1, v0[j]+cost)
+			if i > 0 && j > 0 && t1[i] == t2[j-1] && t1[i-1] == t2[j] {
+				v1[j+1] = levMin(v1[j+1], v2[j-1]+1)
+			}
 		}
+		v2, v0, v1 = v0, v1, v2
+	}
+
+	return v0[len(t2)]
+}
 
-		// copy v1 (current row) to v0 (previous row) for next iteration
-		for j := 0; j < len(v0); j++ {
-			v0[j] = v1[j]
+// DamerauLevenshteinDistance measures the unrestricted Damerau-Levenshtein
+// distance between two strings, counting insertions, deletions,
+// substitutions and transpositions of adjacent characters as one edit.
+// Unlike OSADistance characters may be edited again after being
+// transposed, so DamerauLevenshteinDistance("ca", "abc") is 2.
+// http://en.wikipedia.org/wiki/Damerau%E2%80%93Levenshtein_distance
+func DamerauLevenshteinDistance(source string, target string) int {
+	// degenerate cases
+	if source == target {
+		return 0
+	}
+	t1 := []rune(source)
+	t2 := []rune(target)
+	if len(t1) == 0 {
+		return len(t2)
+	}
+	if len(t2) == 0 {
+		return len(t1)
+	}
+
+	// d is offset by one row and column holding maxDist so that
+	// transpositions reaching before the start are never chosen.
+	maxDist := len(t1) + len(t2)
+	cols := len(t2) + 2
+	d := make([]int, (len(t1)+2)*cols)
+	d[0] = maxDist
+	for i := 0; i <= len(t1); i++ {
+		d[(i+1)*cols] = maxDist
+		d[(i+1)*cols+1] = i
+	}
+	for j := 0; j <= len(t2); j++ {
+		d[j+1] = maxDist
+		d[cols+j+1] = j
+	}
+
+	// last row each character was seen in source
+	lastRow := map[rune]int{}
+	for i := 1; i <= len(t1); i++ {
+		// last column in this row where the characters matched
+		lastMatch := 0
+		for j := 1; j <= len(t2); j++ {
+			k := lastRow[t2[j-1]]
+			l := lastMatch
+			cost := 1
+			if t1[i-1] == t2[j-1] {
+				cost = 0
+				lastMatch = j
+			}
+			d[(i+1)*cols+j+1] = levMin(
+				d[i*cols+j]+cost,              // substitution
+				d[(i+1)*cols+j]+1,             // insertion
+				d[i*cols+j+1]+1,               // deletion
+				d[k*cols+l]+(i-k-1)+1+(j-l-1), // transposition
+			)
 		}
+		lastRow[t1[i-1]] = i
 	}
 
-	return v1[len(t2)]
+	return d[(len(t1)+1)*cols+len(t2)+1]
 }
@@ -1,6 +1,12 @@
 package twine
 
 import (
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -65,3 +71,319 @@ func TestLevenshteinDistance(t *testing.T) {
 		}
 	}
 }
+
+var osaTests = []struct {
+	source   string
+	target   string
+	distance int
+}{
+	{"", "", 0},
+	{"", "abc", 3},
+	{"abc", "", 3},
+	{"abc", "abc", 0},
+	{"teh", "the", 1},
+	{"ab", "ba", 1},
+	{"abcdef", "badcfe", 3},
+	{"ca", "abc", 3},
+	{"library", "librar", 1},
+	{"a cat", "an act", 2},
+	{"kitten", "sitting", 3},
+	// unicode
+	{"Schüßler", "Schßüler", 1},
+	{"Schüßler", "Schübler", 1},
+}
+
+func TestOSADistance(t *testing.T) {
+	for _, tt := range osaTests {
+		res := OSADistance(tt.source, tt.target)
+		if res != tt.distance {
+			t.Errorf("OSADistance(%s, %s) => %d, want %d", tt.source, tt.target, res, tt.distance)
+		}
+	}
+}
+
+var damerauLevenshteinTests = []struct {
+	source   string
+	target   string
+	distance int
+}{
+	{"", "", 0},
+	{"", "abc", 3},
+	{"abc", "", 3},
+	{"abc", "abc", 0},
+	{"teh", "the", 1},
+	{"ab", "ba", 1},
+	{"abcdef", "badcfe", 3},
+	{"ca", "abc", 2},
+	{"a cat", "an abct", 3},
+	{"kitten", "sitting", 3},
+	{"specter", "spectre", 1},
+	// unicode
+	{"Schüßler", "Schßüler", 1},
+	{"üß", "ßxü", 2},
+}
+
+func TestDamerauLevenshteinDistance(t *testing.T) {
+	for _, tt := range damerauLevenshteinTests {
+		res := DamerauLevenshteinDistance(tt.source, tt.target)
+		if res != tt.distance {
+			t.Errorf("DamerauLevenshteinDistance(%s, %s) => %d, want %d", tt.source, tt.target, res, tt.distance)
+		}
+	}
+}
+
+// TestDistanceOrdering checks that allowing transpositions never increases
+// the distance and that the unrestricted distance is never above the
+// restricted one.
+func TestDistanceOrdering(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	word := func() string {
+		b := make([]byte, rnd.Intn(7))
+		for i := range b {
+			b[i] = "abc"[rnd.Intn(3)]
+		}
+		return string(b)
+	}
+	for i := 0; i < 2000; i++ {
+		a, b := word(), word()
+		lev := LevenshteinDistance(a, b)
+		osa := OSADistance(a, b)
+		dl := DamerauLevenshteinDistance(a, b)
+		if dl > osa || osa > lev {
+			t.Fatalf("%s, %s => damerau %d, osa %d, levenshtein %d", a, b, dl, osa, lev)
+		}
+		if dl != DamerauLevenshteinDistance(b, a) || osa != OSADistance(b, a) {
+			t.Fatalf("%s, %s => not symmetric", a, b)
+		}
+	}
+}
+
+var levWithinTests = []struct {
+	source   string
+	target   string
+	k        int
+	distance int
+	within   bool
+}{
+	{"", "", 0, 0, true},
+	{"abc", "abc", 0, 0, true},
+	{"", "abc", 3, 3, true},
+	{"", "abc", 2, 3, false},
+	{"abc", "", 2, 3, false},
+	{"kitten", "sitting", 3, 3, true},
+	{"kitten", "sitting", 2, 3, false},
+	{"library", "librar", 1, 1, true},
+	{"abcdef", "fedcba", 2, 3, false},
+	{"abc", "xyz", -1, 0, false},
+	// unicode
+	{"Schüßler", "Schübler", 1, 1, true},
+	{"Schüßler", "Schüler", 0, 1, false},
+}
+
+func TestLevenshteinWithin(t *testing.T) {
+	for _, tt := range levWithinTests {
+		d, ok := LevenshteinWithin(tt.source, tt.target, tt.k)
+		if d != tt.distance || ok != tt.within {
+			t.Errorf("LevenshteinWithin(%s, %s, %d) => %d %v, want %d %v",
+				tt.source, tt.target, tt.k, d, ok, tt.distance, tt.within)
+		}
+	}
+}
+
+// TestLevenshteinWithinAgrees checks LevenshteinWithin against
+// LevenshteinDistance on random strings for every k around the distance.
+func TestLevenshteinWithinAgrees(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	word := func() string {
+		b := make([]byte, rnd.Intn(9))
+		for i := range b {
+			b[i] = "abcd"[rnd.Intn(4)]
+		}
+		return string(b)
+	}
+	for i := 0; i < 2000; i++ {
+		a, b := word(), word()
+		want := LevenshteinDistance(a, b)
+		for k := 0; k <= want+2; k++ {
+			d, ok := LevenshteinWithin(a, b, k)
+			if ok != (want <= k) || (ok && d != want) || (!ok && d != k+1) {
+				t.Fatalf("LevenshteinWithin(%s, %s, %d) => %d %v, distance %d", a, b, k, d, ok, want)
+			}
+		}
+	}
+}
+
+func BenchmarkLevenshteinDistance(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		LevenshteinDistance("interoperability", "interpretability")
+	}
+}
+
+func BenchmarkLevenshteinWithin(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		LevenshteinWithin("interoperability", "interpretability", 2)
+	}
+}
+
+// TestLevenshteinMyers checks the bit-vector distance against the row by
+// row computation on random strings, including patterns of exactly 64
+// runes and strings too long for the fast path.
+func TestLevenshteinMyers(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	alphabet := []rune("abcü߀")
+	word := func(n int) []rune {
+		w := make([]rune, n)
+		for i := range w {
+			w[i] = alphabet[rnd.Intn(len(alphabet))]
+		}
+		return w
+	}
+	for _, n := range []int{1, 2, 8, 63, 64, 65, 80} {
+		for i := 0; i < 200; i++ {
+			a, b := word(1+rnd.Intn(n)), word(1+rnd.Intn(n))
+			want := levenshteinTwoRow(a, b)
+			if res := LevenshteinDistance(string(a), string(b)); res != want {
+				t.Fatalf("LevenshteinDistance(%s, %s) => %d, want %d", string(a), string(b), res, want)
+			}
+		}
+	}
+}
+
+// vocabulary returns pairs of words from big.txt.gz to benchmark with.
+func vocabulary(b *testing.B) [][2]string {
+	fi, err := os.Open("big.txt.gz")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer fi.Close()
+	mj, err := NewMumboJumbo(fi)
+	if err != nil {
+		b.Fatal(err)
+	}
+	words := make([]string, 0, len(mj.Frequencies))
+	for word := range mj.Frequencies {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	pairs := make([][2]string, 0, len(words))
+	for i := 1; i < len(words); i++ {
+		pairs = append(pairs, [2]string{words[i-1], words[len(words)-i]})
+	}
+	return pairs
+}
+
+func benchmarkVocabulary(b *testing.B, distance func(a, b string) int) {
+	pairs := vocabulary(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := pairs[i%len(pairs)]
+		distance(p[0], p[1])
+	}
+}
+
+func BenchmarkVocabularyLevenshteinDistance(b *testing.B) {
+	benchmarkVocabulary(b, LevenshteinDistance)
+}
+
+func BenchmarkVocabularyTwoRow(b *testing.B) {
+	benchmarkVocabulary(b, func(source, target string) int {
+		return levenshteinTwoRow([]rune(source), []rune(target))
+	})
+}
+
+var levenshteinCalculatorTests = []struct {
+	source string
+	target string
+}{
+	{"interoperability", "interpretability"},
+	{"Schüßler", "Schübler"},
+	{strings.Repeat("abcdefghij", 8), strings.Repeat("abcdefghik", 8)},
+}
+
+func TestLevenshteinCalculator(t *testing.T) {
+	l := NewLevenshtein()
+	for _, tt := range levTests {
+		if res := l.Distance(tt.source, tt.target); res != tt.distance {
+			t.Errorf("Distance(%s, %s) => %d, want %d", tt.source, tt.target, res, tt.distance)
+		}
+	}
+	for _, tt := range levenshteinCalculatorTests {
+		want := levenshteinTwoRow([]rune(tt.source), []rune(tt.target))
+		for i := 0; i < 2; i++ {
+			if res := l.Distance(tt.source, tt.target); res != want {
+				t.Errorf("Distance(%s, %s) => %d, want %d", tt.source, tt.target, res, want)
+			}
+		}
+		allocs := testing.AllocsPerRun(100, func() {
+			l.Distance(tt.source, tt.target)
+			l.Within(tt.source, tt.target, 2)
+		})
+		if allocs != 0 {
+			t.Errorf("Distance(%s, %s) => %v allocs, want 0", tt.source, tt.target, allocs)
+		}
+	}
+}
+
+func benchmarkCalculator(b *testing.B, source, target string) {
+	l := NewLevenshtein()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Distance(source, target)
+	}
+}
+
+func BenchmarkLevenshteinCalculatorASCII(b *testing.B) {
+	benchmarkCalculator(b, "interoperability", "interpretability")
+}
+
+func BenchmarkLevenshteinCalculatorUnicode(b *testing.B) {
+	benchmarkCalculator(b, "Schüßlerstraße", "Schüblerstrasse")
+}
+
+func BenchmarkLevenshteinCalculatorLong(b *testing.B) {
+	benchmarkCalculator(b, strings.Repeat("abcdefghij", 8), strings.Repeat("abcdefghik", 8))
+}
+
+func BenchmarkLevenshteinPool(b *testing.B) {
+	pool := sync.Pool{New: func() interface{} { return NewLevenshtein() }}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l := pool.Get().(*Levenshtein)
+			l.Distance("Schüßlerstraße", "Schüblerstrasse")
+			pool.Put(l)
+		}
+	})
+}
+
+var similarityTests = []struct {
+	source     string
+	target     string
+	similarity float64
+	yujianBo   float64
+}{
+	{"", "", 1, 1},
+	{"abc", "abc", 1, 1},
+	{"", "abc", 0, 0},
+	{"abc", "xyz", 0, 1.0 / 3},
+	{"abcd", "", 0, 0},
+	{"abcd", "wxyz", 0, 1.0 / 3},
+	{"cat", "cut", 2.0 / 3, 5.0 / 7},
+	{"ab", "abcd", 0.5, 0.5},
+	{"kitten", "sitting", 4.0 / 7, 10.0 / 16},
+	// unicode
+	{"Schüßler", "Schübler", 7.0 / 8, 15.0 / 17},
+}
+
+func TestLevenshteinSimilarity(t *testing.T) {
+	for _, tt := range similarityTests {
+		res := LevenshteinSimilarity(tt.source, tt.target)
+		if math.Abs(res-tt.similarity) > 1e-9 {
+			t.Errorf("LevenshteinSimilarity(%s, %s) => %v, want %v", tt.source, tt.target, res, tt.similarity)
+		}
+		res = YujianBoSimilarity(tt.source, tt.target)
+		if math.Abs(res-tt.yujianBo) > 1e-9 {
+			t.Errorf("YujianBoSimilarity(%s, %s) => %v, want %v", tt.source, tt.target, res, tt.yujianBo)
+		}
+	}
+}
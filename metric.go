@@ -0,0 +1,120 @@
+package twine
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Metric measures how far apart two strings are, 0 meaning identical.
+type Metric interface {
+	Distance(source, target string) float64
+}
+
+// MetricFunc adapts a function to a Metric.
+type MetricFunc func(source, target string) float64
+
+// Distance returns f(source, target).
+func (f MetricFunc) Distance(source, target string) float64 {
+	return f(source, target)
+}
+
+// DistanceMetric adapts an edit distance such as LevenshteinDistance to
+// a Metric.
+func DistanceMetric(distance DistanceFunc) Metric {
+	return MetricFunc(func(source, target string) float64 {
+		return float64(distance(source, target))
+	})
+}
+
+// NormalizedMetric is a Metric whose distances lie between 0 and 1,
+// such as the metrics made by SimilarityMetric. MumboJumbo multiplies
+// them by the length of the longer string to weigh them as edits.
+type NormalizedMetric interface {
+	Metric
+	Normalized()
+}
+
+// normalizedFunc is a MetricFunc measuring distances between 0 and 1.
+type normalizedFunc func(source, target string) float64
+
+// Distance returns f(source, target).
+func (f normalizedFunc) Distance(source, target string) float64 {
+	return f(source, target)
+}
+
+// Normalized marks f as a NormalizedMetric.
+func (f normalizedFunc) Normalized() {}
+
+// SimilarityMetric adapts a similarity between 0 and 1 such as
+// JaroWinkler to a NormalizedMetric measuring 1 - similarity.
+func SimilarityMetric(similarity func(source, target string) float64) Metric {
+	return normalizedFunc(func(source, target string) float64 {
+		return 1 - similarity(source, target)
+	})
+}
+
+// Distance returns the WeightedLevenshtein distance between two strings
+// so that a CostModel can be used as a Metric.
+func (c CostModel) Distance(source, target string) float64 {
+	return WeightedLevenshtein(source, target, c)
+}
+
+var (
+	metricsMu sync.RWMutex
+	metrics   = map[string]Metric{
+		"levenshtein":            DistanceMetric(LevenshteinDistance),
+		"osa":                    DistanceMetric(OSADistance),
+		"damerau-levenshtein":    DistanceMetric(DamerauLevenshteinDistance),
+		"levenshtein-similarity": SimilarityMetric(LevenshteinSimilarity),
+		"yujian-bo":              SimilarityMetric(YujianBoSimilarity),
+		"jaro":                   SimilarityMetric(Jaro),
+		"jaro-winkler":           SimilarityMetric(JaroWinkler),
+		"qgram":                  DistanceMetric(QGram{Q: 2, Pad: true}.Distance),
+		"jaccard":                SimilarityMetric(QGram{Q: 2, Pad: true}.Jaccard),
+		"dice":                   SimilarityMetric(QGram{Q: 2, Pad: true}.Dice),
+		"cosine":                 SimilarityMetric(QGram{Q: 2, Pad: true}.Cosine),
+		"qwerty":                 QWERTY.CostModel(),
+		"token-sort":             SimilarityMetric(TokenSortRatio),
+		"token-set":              SimilarityMetric(TokenSetRatio),
+		"partial":                SimilarityMetric(PartialRatio),
+		"monge-elkan":            SimilarityMetric(mongeElkanJaroWinkler),
+	}
+)
+
+// RegisterMetric makes a metric available by name to LookupMetric,
+// replacing any metric registered under the same name.
+func RegisterMetric(name string, metric Metric) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	metrics[name] = metric
+}
+
+// LookupMetric returns the metric registered under name or an error
+// wrapping ErrNotFound. The built in metrics are levenshtein, osa,
+// damerau-levenshtein, levenshtein-similarity, yujian-bo, jaro,
+// jaro-winkler, the padded bigram qgram, jaccard, dice and cosine, the
+// keyboard weighted qwerty, and the word based token-sort, token-set,
+// partial and monge-elkan over jaro-winkler. Similarities are turned
+// into distances as 1 - similarity.
+func LookupMetric(name string) (Metric, error) {
+	metricsMu.RLock()
+	defer metricsMu.RUnlock()
+	metric, ok := metrics[name]
+	if !ok {
+		return nil, fmt.Errorf("metric %q: %w", name, ErrNotFound)
+	}
+	return metric, nil
+}
+
+// Metrics returns the sorted names of the registered metrics.
+func Metrics() []string {
+	metricsMu.RLock()
+	defer metricsMu.RUnlock()
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,104 @@
+package twine
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+var metricTests = []struct {
+	name     string
+	source   string
+	target   string
+	distance float64
+}{
+	{"levenshtein", "kitten", "sitting", 3},
+	{"osa", "ca", "ac", 1},
+	{"damerau-levenshtein", "ca", "abc", 2},
+	{"levenshtein-similarity", "abcd", "abce", 0.25},
+	{"yujian-bo", "abcd", "", 1},
+	{"jaro", "abc", "abc", 0},
+	{"jaro-winkler", "abc", "xyz", 1},
+	{"qgram", "ab", "ab", 0},
+	{"jaccard", "abc", "xyz", 1},
+	{"dice", "night", "night", 0},
+	{"cosine", "", "", 0},
+	{"qwerty", "cat", "cst", 0.5},
+	{"token-sort", "Smith, John", "john smith", 0},
+	{"token-set", "abc", "xyz", 1},
+	{"partial", "Main St", "12 Main St", 0},
+	{"monge-elkan", "Smith, John", "john smith", 0},
+}
+
+func TestLookupMetric(t *testing.T) {
+	for _, tt := range metricTests {
+		metric, err := LookupMetric(tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res := metric.Distance(tt.source, tt.target)
+		if math.Abs(res-tt.distance) > 1e-9 {
+			t.Errorf("LookupMetric(%s).Distance(%s, %s) => %v, want %v", tt.name, tt.source, tt.target, res, tt.distance)
+		}
+	}
+}
+
+func TestLookupMetricNotFound(t *testing.T) {
+	if _, err := LookupMetric("nope"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("LookupMetric(nope) => %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestRegisterMetric(t *testing.T) {
+	length := MetricFunc(func(source, target string) float64 {
+		return math.Abs(float64(len(source) - len(target)))
+	})
+	RegisterMetric("test-length", length)
+	defer func() {
+		metricsMu.Lock()
+		delete(metrics, "test-length")
+		metricsMu.Unlock()
+	}()
+	metric, err := LookupMetric("test-length")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := metric.Distance("a", "abc"); res != 2 {
+		t.Errorf("Distance(a, abc) => %v, want 2", res)
+	}
+	found := false
+	for _, name := range Metrics() {
+		found = found || name == "test-length"
+	}
+	if !found {
+		t.Errorf("Metrics() => %v, want test-length", Metrics())
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	names := Metrics()
+	if len(names) != len(metricTests) {
+		t.Errorf("Metrics() => %v, want %d names", names, len(metricTests))
+	}
+	for i := 1; i < len(names); i++ {
+		if names[i-1] > names[i] {
+			t.Errorf("Metrics() => %v, want sorted", names)
+		}
+	}
+}
+
+func TestNormalizedMetric(t *testing.T) {
+	for _, name := range []string{"levenshtein", "qgram", "qwerty"} {
+		metric, _ := LookupMetric(name)
+		if _, ok := metric.(NormalizedMetric); ok {
+			t.Errorf("LookupMetric(%s) => NormalizedMetric, want edit distance", name)
+		}
+	}
+	for _, name := range []string{"jaro-winkler", "cosine", "token-set"} {
+		metric, _ := LookupMetric(name)
+		if _, ok := metric.(NormalizedMetric); !ok {
+			t.Errorf("LookupMetric(%s) => %T, want NormalizedMetric", name, metric)
+		}
+	}
+	var _ NormalizedMetric = &SoftTFIDF{}
+}
@@ -4,40 +4,191 @@ import (
 	"bufio"
 	"bytes"
 	"compress/gzip"
+	"encoding/gob"
 	"fmt"
 	"io"
 	"math"
+	"sort"
 	"strings"
 	"sync"
 	"unicode"
+	"unicode/utf8"
 
 	log "github.com/golang/glog"
+	"github.com/rivo/uniseg"
+)
+
+// editProbability is the chance of a single typing error used by the
+// noisy channel model, each edit multiplies a candidate's probability by it.
+const editProbability = 0.01
+
+// checkSuggestions is the number of suggestions Check returns for
+// each misspelling.
+const checkSuggestions = 5
+
+// indexVersion is the version of the format written by WriteTo.
+const indexVersion = 1
+
+// Strategy selects how MumboJumbo finds candidate corrections.
+type Strategy int
+
+const (
+	// StrategyMetaphone looks up words sharing a double metaphone code
+	// with the input.
+	StrategyMetaphone Strategy = iota
+	// StrategyEdits looks up the words within a few edits of the input,
+	// two by default, in an index of the strings obtained by deleting
+	// runes from each dictionary word.
+	StrategyEdits
+	// StrategyBoth merges the candidates of StrategyMetaphone and
+	// StrategyEdits.
+	StrategyBoth
+)
+
+// Ranking selects how MumboJumbo orders suggestions.
+type Ranking int
+
+const (
+	// RankNoisyChannel orders suggestions by Score, weighing the edit
+	// distance against how common the word is.
+	RankNoisyChannel Ranking = iota
+	// RankSimilarity orders suggestions by Similarity so that inputs of
+	// any length are ranked on the same scale, ties are broken by
+	// frequency.
+	RankSimilarity
 )
 
 // MumboJumbo is a spell checker.
 type MumboJumbo struct {
-	Metaphones map[string]map[string]struct{}
-	CodeLength int
-	mu         *sync.Mutex
+	Metaphones  map[string]map[string]struct{}
+	Frequencies map[string]int // occurrences of each word in the corpus
+	TotalWords  int            // total words read from the corpus
+	CodeLength  int
+	Strategy    Strategy
+
+	tokenizer     Tokenizer
+	normalizer    func(string) string
+	unicode       Unicode
+	distance      DistanceFunc
+	metric        Metric
+	maxDistance   int
+	maxRelative   float64
+	minFrequency  int
+	minSimilarity float64
+	ranking       Ranking
+	minWordLength int
+	stopWords     map[string]struct{}
+	detectGzip    bool
+
+	maxEdits int
+	// deletes maps the strings obtained by deleting up to maxEdits runes
+	// from dictionary words to those words, nil until a strategy using
+	// edits needs it.
+	deletes map[string][]string
+	mu      *sync.Mutex
+}
+
+// Misspelling is a word found by Check that is not in the dictionary.
+type Misspelling struct {
+	Word        string       // the word as it appears in the text
+	Offset      int          // byte offset of Word in the text
+	RuneOffset  int          // rune offset of Word in the text
+	Suggestions []Suggestion // ranked corrections, best first
+}
+
+// DistanceError is returned when every candidate for Input is further
+// away than the maximum distance allowed by WithMaxDistance or
+// WithMaxRelativeDistance.
+type DistanceError struct {
+	Input       string // the word a suggestion was asked for
+	Word        string // the closest rejected candidate
+	Distance    int    // distance between Input and Word
+	MaxDistance int    // the maximum distance allowed for Input
+}
+
+func (e *DistanceError) Error() string {
+	return fmt.Sprintf("%v for %q within distance %d, closest %q is at %d",
+		ErrNoSuggestion, e.Input, e.MaxDistance, e.Word, e.Distance)
+}
+
+// Unwrap returns ErrNoSuggestion.
+func (e *DistanceError) Unwrap() error {
+	return ErrNoSuggestion
+}
+
+// Suggestion is a candidate correction for an input word.
+type Suggestion struct {
+	Word      string // the dictionary word, cased like the input
+	Distance  int    // edit distance between the input and Word
+	Code      string // the metaphone code of the input Word was found under, if any
+	Frequency int    // occurrences of Word in the corpus
+	// Cost is the metric distance between the input and Word in edits
+	// (see WithMetric) used for ranking, it equals Distance unless a
+	// metric or cost model is configured.
+	Cost float64
+	// Similarity is Distance normalized by the length of the longer of
+	// the input and Word, between 0 and 1 with 1 being identical.
+	Similarity float64
+	// Score is the log probability of Word being the intended word given
+	// the input, higher is better.
+	Score float64
 }
 
 // NewMumboJumbo reads from io and attempts to first
 // unzip a gzip io, if it fails it just reads the file
-// line by line.
-func NewMumboJumbo(in io.Reader, codeLength int) (*MumboJumbo, error) {
-	mj := &MumboJumbo{
-		Metaphones: map[string]map[string]struct{}{},
-		CodeLength: codeLength,
-		mu:         &sync.Mutex{},
+// line by line. The spell checker can be tuned with opts.
+func NewMumboJumbo(in io.Reader, opts ...Option) (*MumboJumbo, error) {
+	mj := newMumboJumbo(opts...)
+	if err := mj.AddReader(in); err != nil {
+		return nil, err
 	}
+	log.V(1).Infof("total words:%d metaphones: %d", mj.TotalWords, len(mj.Metaphones))
+	/*
+		for k, v := range mj.Metaphones {
+			log.Error(k, " ", v)
+		}
+	*/
+
+	return mj, nil
+}
+
+// AddReader reads words from in and adds them to the dictionary, unless
+// disabled it attempts to first unzip a gzip io, if it fails it just
+// reads the file line by line.
+func (m *MumboJumbo) AddReader(in io.Reader) error {
+	r, err := corpusReader(in, m.detectGzip)
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		words := m.parseLine(scanner.Text())
+		m.mu.Lock()
+		for _, word := range words {
+			m.addWord(word)
+		}
+		m.mu.Unlock()
+	}
+	if err := scanner.Err(); err != nil {
+		log.Error(err)
+		return err
+	}
+	return nil
+}
+
+// corpusReader buffers in and, when detectGzip is set and in starts
+// with the gzip magic number, unzips it. If unzipping fails in is read
+// as is.
+func corpusReader(in io.Reader, detectGzip bool) (*bufio.Reader, error) {
 	r := bufio.NewReader(in)
 	gzipCheck, err := r.Peek(2)
-	if err != nil || len(gzipCheck) < 2 {
+	if err != nil && err != io.EOF {
 		log.Error(err)
 		return nil, err
 	}
-	if gzipCheck[0] == 31 && gzipCheck[1] == 139 {
-		var err error
+	if detectGzip && len(gzipCheck) == 2 && gzipCheck[0] == 31 && gzipCheck[1] == 139 {
 		fz, err := gzip.NewReader(r)
 		if err != nil {
 			log.Error(err)
@@ -45,67 +196,295 @@ func NewMumboJumbo(in io.Reader, codeLength int) (*MumboJumbo, error) {
 			r = bufio.NewReader(fz)
 		}
 	}
+	return r, nil
+}
 
-	scanner := bufio.NewScanner(r)
-	scanner.Split(bufio.ScanWords)
-	totalWords := 0
-	for scanner.Scan() {
-		words := mj.parseLine(scanner.Text())
-		for _, word := range words {
-			if len(word) == 0 {
-				continue
-			}
-			totalWords++
-			res, err := DoubleMetaphone(word, mj.CodeLength)
-			if err != nil {
-				log.Error(err)
-				continue
-			}
-			if res[0] != "" {
-				if _, ok := mj.Metaphones[res[0]]; !ok {
-					mj.Metaphones[res[0]] = map[string]struct{}{}
+// AddWord adds the words found in word to the dictionary, incrementing
+// their frequency if they are already known.
+func (m *MumboJumbo) AddWord(word string) {
+	words := m.parseLine(word)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, w := range words {
+		m.addWord(w)
+	}
+}
+
+// addWord counts a parsed word and indexes it under its metaphone codes.
+// The caller must hold the lock.
+func (m *MumboJumbo) addWord(word string) {
+	if len(word) == 0 {
+		return
+	}
+	m.TotalWords++
+	m.Frequencies[word]++
+	if m.Frequencies[word] > 1 {
+		return
+	}
+	if m.deletes != nil {
+		m.indexDeletes(word)
+	}
+	res, err := DoubleMetaphone(word, m.CodeLength)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, code := range res {
+		if code == "" {
+			continue
+		}
+		if _, ok := m.Metaphones[code]; !ok {
+			m.Metaphones[code] = map[string]struct{}{}
+		}
+		m.Metaphones[code][word] = struct{}{}
+	}
+}
+
+// RemoveWord removes the words found in word from the dictionary along
+// with their frequency, splitting and normalizing it like AddWord. If
+// any of them is not in the dictionary nothing is removed and
+// ErrNotFound is returned.
+func (m *MumboJumbo) RemoveWord(word string) error {
+	words := m.parseLine(word)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(words) == 0 {
+		return ErrNotFound
+	}
+	for _, w := range words {
+		if _, ok := m.Frequencies[w]; !ok {
+			return ErrNotFound
+		}
+	}
+	for _, w := range words {
+		m.removeWord(w)
+	}
+	return nil
+}
+
+// removeWord forgets a parsed word and removes it from the indexes. The
+// caller must hold the lock.
+func (m *MumboJumbo) removeWord(word string) {
+	freq, ok := m.Frequencies[word]
+	if !ok {
+		return
+	}
+	delete(m.Frequencies, word)
+	m.TotalWords -= freq
+	if m.deletes != nil {
+		for del := range deletions(word, m.maxEdits, m.unicode.Graphemes) {
+			words := m.deletes[del]
+			for i, w := range words {
+				if w == word {
+					words = append(words[:i], words[i+1:]...)
+					break
 				}
-				mj.Metaphones[res[0]][word] = struct{}{}
 			}
-			if res[1] != "" {
-				if _, ok := mj.Metaphones[res[1]]; !ok {
-					mj.Metaphones[res[1]] = map[string]struct{}{}
-				}
-				mj.Metaphones[res[1]][word] = struct{}{}
+			if len(words) == 0 {
+				delete(m.deletes, del)
+			} else {
+				m.deletes[del] = words
 			}
 		}
 	}
-	log.V(1).Infof("total words:%d metaphones: %d", totalWords, len(mj.Metaphones))
-	/*
-		for k, v := range mj.Metaphones {
-			log.Error(k, " ", v)
+	res, err := DoubleMetaphone(word, m.CodeLength)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, code := range res {
+		if words, ok := m.Metaphones[code]; ok {
+			delete(words, word)
+			if len(words) == 0 {
+				delete(m.Metaphones, code)
+			}
 		}
-	*/
+	}
+}
+
+// Contains reports whether the words found in word, split and normalized
+// like AddWord, are all in the dictionary.
+func (m *MumboJumbo) Contains(word string) bool {
+	words := m.parseLine(word)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, w := range words {
+		if _, ok := m.Frequencies[w]; !ok {
+			return false
+		}
+	}
+	return len(words) > 0
+}
+
+// indexHeader is written ahead of the index so the format can change
+// without breaking readers of older indexes.
+type indexHeader struct {
+	Version int
+}
+
+// index is the serialized form of a MumboJumbo.
+type index struct {
+	CodeLength  int
+	Strategy    Strategy
+	TotalWords  int
+	Metaphones  map[string][]string
+	Frequencies map[string]int
+}
+
+// countWriter counts the bytes written to the underlying writer.
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// WriteTo writes a versioned gob encoding of the spell checker to w
+// which can be loaded with ReadMumboJumbo. It returns the number of
+// bytes written.
+func (m *MumboJumbo) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	idx := index{
+		CodeLength:  m.CodeLength,
+		Strategy:    m.Strategy,
+		TotalWords:  m.TotalWords,
+		Metaphones:  make(map[string][]string, len(m.Metaphones)),
+		Frequencies: m.Frequencies,
+	}
+	for code, words := range m.Metaphones {
+		list := make([]string, 0, len(words))
+		for word := range words {
+			list = append(list, word)
+		}
+		idx.Metaphones[code] = list
+	}
+
+	cw := &countWriter{w: w}
+	enc := gob.NewEncoder(cw)
+	if err := enc.Encode(indexHeader{Version: indexVersion}); err != nil {
+		log.Error(err)
+		return cw.n, err
+	}
+	if err := enc.Encode(idx); err != nil {
+		log.Error(err)
+		return cw.n, err
+	}
+	return cw.n, nil
+}
+
+// ReadMumboJumbo loads a spell checker previously saved with WriteTo.
+// Only the dictionary, code length and strategy are saved, any other
+// options have to be given again. The saved code length always wins as
+// the index was built with it.
+func ReadMumboJumbo(in io.Reader, opts ...Option) (*MumboJumbo, error) {
+	dec := gob.NewDecoder(in)
+	var hdr indexHeader
+	if err := dec.Decode(&hdr); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	if hdr.Version != indexVersion {
+		err := fmt.Errorf("%w %d", ErrIndexVersion, hdr.Version)
+		log.Error(err)
+		return nil, err
+	}
+	var idx index
+	if err := dec.Decode(&idx); err != nil {
+		log.Error(err)
+		return nil, err
+	}
 
+	mj := newMumboJumbo(append([]Option{WithStrategy(idx.Strategy)}, opts...)...)
+	mj.CodeLength = idx.CodeLength
+	mj.TotalWords = idx.TotalWords
+	if idx.Frequencies != nil {
+		mj.Frequencies = idx.Frequencies
+	}
+	if mj.deletes != nil {
+		mj.buildDeletes()
+	}
+	for code, list := range idx.Metaphones {
+		words := make(map[string]struct{}, len(list))
+		for _, word := range list {
+			words[word] = struct{}{}
+		}
+		mj.Metaphones[code] = words
+	}
 	return mj, nil
 }
 
-// parseLine sanitizes input as a string and returns an array of
-// lowercased words. Accounts for unicode characters by
-// converting to runes.
-func (m *MumboJumbo) parseLine(line string) []string {
-	words := []string{}
+// Tokenize splits text into runs of unicode letters, keeping the
+// original casing and recording the offset of each word. Combining marks
+// following a letter are part of the word, so decomposed accents are
+// kept.
+func Tokenize(text string) []Token {
+	tokens := []Token{}
 
-	runes := []rune(strings.ToLower(line))
-	var iter bytes.Buffer
-	for _, r := range runes {
+	start, runeStart, runes := -1, 0, 0
+	for i, r := range text {
 		switch {
 		case unicode.IsLetter(r):
-			iter.WriteRune(r)
+			if start < 0 {
+				start, runeStart = i, runes
+			}
+		case start >= 0 && unicode.In(r, unicode.Mn, unicode.Mc, unicode.Me):
 		default:
-			if iter.Len() > 0 {
-				words = append(words, iter.String())
-				iter.Reset()
+			if start >= 0 {
+				tokens = append(tokens, Token{text[start:i], start, runeStart})
+				start = -1
 			}
 		}
+		runes++
+	}
+	if start >= 0 {
+		tokens = append(tokens, Token{text[start:], start, runeStart})
 	}
-	if iter.Len() > 0 {
-		words = append(words, iter.String())
+
+	return tokens
+}
+
+// tokenize splits text with the configured tokenizer.
+func (m *MumboJumbo) tokenize(text string) []Token {
+	if m.tokenizer == nil {
+		return Tokenize(text)
+	}
+	return m.tokenizer(text)
+}
+
+// normalize normalizes a word with the configured normalizer.
+func (m *MumboJumbo) normalize(word string) string {
+	word = m.unicode.Normalize(word)
+	if m.normalizer == nil {
+		return strings.ToLower(word)
+	}
+	return m.normalizer(word)
+}
+
+// ignored reports whether a normalized word is a stop word or too short
+// to be indexed or checked.
+func (m *MumboJumbo) ignored(word string) bool {
+	if _, ok := m.stopWords[word]; ok {
+		return true
+	}
+	return len(word) == 0 || utf8.RuneCountInString(word) < m.minWordLength
+}
+
+// parseLine sanitizes input as a string and returns an array of
+// normalized words, by default lowercased. Stop words and words that
+// are too short are left out.
+func (m *MumboJumbo) parseLine(line string) []string {
+	words := []string{}
+	for _, tok := range m.tokenize(line) {
+		word := m.normalize(tok.Text)
+		if m.ignored(word) {
+			continue
+		}
+		words = append(words, word)
 	}
 
 	return words
@@ -128,40 +507,376 @@ func (m *MumboJumbo) sanitizeWord(word string) string {
 }
 
 // Suggest takes an input word and returns the best suggestion for the word.
-// If there's no suggestions an error is returned.
+// If there's no suggestions ErrNoSuggestion is returned.
 func (m *MumboJumbo) Suggest(input string) (string, error) {
+	suggs, err := m.SuggestN(input, 1)
+	if err != nil {
+		return "", err
+	}
+	return suggs[0].Word, nil
+}
+
+// SuggestN takes an input word and returns up to n suggestions, best first.
+// Depending on the Strategy candidates are the words sharing the primary
+// or alternate metaphone code of the input and the words within two
+// deletions, substitutions or insertions of it, see WithMaxEdits. Words are
+// compared after normalization and returned cased like the input (see
+// matchCase). They are ranked with a noisy channel model combining the
+// probability of the word in the corpus with the probability of making
+// as many typing errors as the edit distance. Ties are broken by distance,
+// corpus frequency and finally alphabetically.
+// If n <= 0 all candidates are returned. If there's no suggestions
+// ErrNoSuggestion is returned, wrapped in a *DistanceError if candidates
+// were only dropped for being too far from the input. Distances are only
+// computed within a band, see LevenshteinWithin, when a maximum distance
+// is set, n doesn't bound them as ranking isn't by distance alone.
+func (m *MumboJumbo) SuggestN(input string, n int) ([]Suggestion, error) {
+	original := input
+	input = m.normalize(input)
 	dm, err := DoubleMetaphone(input, m.CodeLength)
 	if err != nil {
 		log.Error(err)
-		return "", err
+		return nil, err
+	}
+	maxDist := m.maxDistanceFor(input)
+	var tooFar []string
+	lev := levenshteinPool.Get().(*Levenshtein)
+	defer levenshteinPool.Put(lev)
+	seen := map[string]struct{}{}
+	suggMap := map[string]Suggestion{}
+	add := func(word, code string) {
+		// a word found under the primary code keeps it
+		if _, ok := seen[word]; ok {
+			return
+		}
+		seen[word] = struct{}{}
+		freq, ok := m.Frequencies[word]
+		if (!ok && code == "") || freq < m.minFrequency {
+			return
+		}
+		dist, ok := m.distanceWithin(lev, input, word, maxDist)
+		if !ok {
+			tooFar = append(tooFar, word)
+			return
+		}
+		sim := similarity(dist, m.length(input), m.length(word))
+		if sim < m.minSimilarity {
+			return
+		}
+		cost := float64(dist)
+		if m.metric != nil {
+			cost = metricCost(m.metric, input, word)
+		}
+		suggMap[word] = Suggestion{
+			Word:       word,
+			Distance:   dist,
+			Code:       code,
+			Frequency:  freq,
+			Cost:       cost,
+			Similarity: sim,
+			Score:      m.score(freq, cost),
+		}
 	}
-	suggMap := map[string]int{}
 	m.mu.Lock()
-	for k, _ := range m.Metaphones[dm[0]] {
-		suggMap[k] = LevenshteinDistance(input, k)
+	if m.Strategy != StrategyEdits {
+		for _, code := range dm {
+			if code == "" {
+				continue
+			}
+			for word := range m.Metaphones[code] {
+				add(word, code)
+			}
+		}
+	}
+	if m.Strategy != StrategyMetaphone {
+		if m.deletes == nil {
+			m.buildDeletes()
+		}
+		for del := range deletions(input, m.maxEdits, m.unicode.Graphemes) {
+			for _, word := range m.deletes[del] {
+				if _, ok := seen[word]; ok {
+					continue
+				}
+				if m.withinEdits(lev, input, word) {
+					add(word, "")
+				}
+			}
+		}
 	}
 	m.mu.Unlock()
+	if len(suggMap) == 0 && len(tooFar) > 0 {
+		return nil, m.distanceError(original, input, tooFar, maxDist)
+	}
 	if len(suggMap) == 0 {
-		return "", fmt.Errorf("no suggestion")
+		return nil, ErrNoSuggestion
 	}
 
-	if dm[1] != "" {
-		alts, ok := m.Metaphones[dm[1]]
-		if ok {
-			for alt, _ := range alts {
-				suggMap[alt] = LevenshteinDistance(input, alt)
+	suggs := make([]Suggestion, 0, len(suggMap))
+	for _, sugg := range suggMap {
+		suggs = append(suggs, sugg)
+	}
+	if m.ranking == RankSimilarity {
+		sort.Sort(bySimilarity(suggs))
+	} else {
+		sort.Sort(bySuggestion(suggs))
+	}
+	if n > 0 && n < len(suggs) {
+		suggs = suggs[:n]
+	}
+	for i := range suggs {
+		suggs[i].Word = matchCase(original, suggs[i].Word)
+	}
+
+	return suggs, nil
+}
+
+// maxDistanceFor returns the largest distance a suggestion for input
+// may be at, or -1 if there is no limit.
+func (m *MumboJumbo) maxDistanceFor(input string) int {
+	max := -1
+	if m.maxDistance > 0 {
+		max = m.maxDistance
+	}
+	if m.maxRelative > 0 {
+		// allow for floating point error, 0.29 * 100 is just under 29
+		rel := int(math.Floor(m.maxRelative*float64(m.length(input)) + 1e-9))
+		if max < 0 || rel < max {
+			max = rel
+		}
+	}
+	return max
+}
+
+// length returns the number of characters in s, counting grapheme
+// clusters when configured with WithUnicode.
+func (m *MumboJumbo) length(s string) int {
+	if m.unicode.Graphemes {
+		return uniseg.GraphemeClusterCount(s)
+	}
+	return utf8.RuneCountInString(s)
+}
+
+// withinEdits reports whether word is within the maximum number of edits
+// of input, see WithMaxEdits.
+func (m *MumboJumbo) withinEdits(lev *Levenshtein, input, word string) bool {
+	if m.unicode.Graphemes {
+		return lev.GraphemeDistance(input, word) <= m.maxEdits
+	}
+	_, ok := lev.Within(input, word, m.maxEdits)
+	return ok
+}
+
+// distanceWithin returns the distance between input and word and whether
+// it is at most maxDist, a negative maxDist allowing any distance. The
+// default Levenshtein distance is computed with lev and stops once over
+// maxDist, the distance returned is then only a lower bound.
+func (m *MumboJumbo) distanceWithin(lev *Levenshtein, input, word string, maxDist int) (int, bool) {
+	if m.distance == nil && m.unicode.Graphemes {
+		dist := lev.GraphemeDistance(input, word)
+		return dist, maxDist < 0 || dist <= maxDist
+	}
+	if m.distance == nil {
+		if maxDist >= 0 {
+			return lev.Within(input, word, maxDist)
+		}
+		return lev.Distance(input, word), true
+	}
+	dist := m.distance(input, word)
+	return dist, maxDist < 0 || dist <= maxDist
+}
+
+// distanceError returns a *DistanceError naming the closest of the words
+// rejected for being further than maxDist from input.
+func (m *MumboJumbo) distanceError(original, input string, words []string, maxDist int) *DistanceError {
+	derr := &DistanceError{Input: original, MaxDistance: maxDist}
+	for _, word := range words {
+		dist := m.distanceFunc()(input, word)
+		if derr.Word == "" || dist < derr.Distance ||
+			(dist == derr.Distance && word < derr.Word) {
+			derr.Word, derr.Distance = word, dist
+		}
+	}
+	return derr
+}
+
+// distanceFunc returns the configured distance.
+func (m *MumboJumbo) distanceFunc() DistanceFunc {
+	if m.distance == nil && m.unicode.Graphemes {
+		return GraphemeLevenshteinDistance
+	}
+	if m.distance == nil {
+		return LevenshteinDistance
+	}
+	return m.distance
+}
+
+// deletions returns word and every distinct string obtained by deleting
+// up to depth runes from it, or grapheme clusters when graphemes is set.
+// A word and the input are within depth Levenshtein edits of each other
+// only if they share such a string, a substitution being a deletion from
+// both.
+func deletions(word string, depth int, graphemes bool) map[string]struct{} {
+	dels := map[string]struct{}{word: {}}
+	level := []string{word}
+	for d := 0; d < depth; d++ {
+		var next []string
+		for _, w := range level {
+			// offsets holds the byte offset of every character and the end
+			var offsets []int
+			if graphemes {
+				state := -1
+				for rest := w; len(rest) > 0; {
+					offsets = append(offsets, len(w)-len(rest))
+					_, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+				}
+			} else {
+				for i := range w {
+					offsets = append(offsets, i)
+				}
 			}
+			offsets = append(offsets, len(w))
+			for i := 0; i < len(offsets)-1; i++ {
+				del := w[:offsets[i]] + w[offsets[i+1]:]
+				if _, ok := dels[del]; !ok {
+					dels[del] = struct{}{}
+					next = append(next, del)
+				}
+			}
+		}
+		level = next
+	}
+	return dels
+}
+
+// indexDeletes adds word to the deletion index. The caller must hold the
+// lock.
+func (m *MumboJumbo) indexDeletes(word string) {
+	for del := range deletions(word, m.maxEdits, m.unicode.Graphemes) {
+		m.deletes[del] = append(m.deletes[del], word)
+	}
+}
+
+// buildDeletes indexes every dictionary word by its deletions, after
+// which addWord and RemoveWord keep the index up to date. The caller
+// must hold the lock.
+func (m *MumboJumbo) buildDeletes() {
+	m.deletes = map[string][]string{}
+	for word := range m.Frequencies {
+		m.indexDeletes(word)
+	}
+}
+
+// metricCost returns the distance between input and word under metric
+// in edits, see WithMetric.
+func metricCost(metric Metric, input, word string) float64 {
+	d := metric.Distance(input, word)
+	if _, ok := metric.(NormalizedMetric); ok {
+		d *= float64(levMax(utf8.RuneCountInString(input), utf8.RuneCountInString(word)))
+	}
+	return d
+}
+
+// score returns the log probability of a word seen freq times in the
+// corpus being intended when it costs cost edits to reach from the input.
+// Word counts are add-one smoothed so words missing from Frequencies
+// still rank. The caller must hold the lock.
+func (m *MumboJumbo) score(freq int, cost float64) float64 {
+	p := float64(freq+1) / float64(m.TotalWords+len(m.Frequencies)+1)
+	return math.Log(p) + cost*math.Log(editProbability)
+}
+
+// Check splits text into words the same way the corpus is read and
+// returns every word missing from the dictionary with its position in
+// text and up to checkSuggestions ranked suggestions.
+func (m *MumboJumbo) Check(text string) []Misspelling {
+	misspellings := []Misspelling{}
+	for _, tok := range m.tokenize(text) {
+		word := m.normalize(tok.Text)
+		if m.ignored(word) {
+			continue
+		}
+		m.mu.Lock()
+		_, ok := m.Frequencies[word]
+		m.mu.Unlock()
+		if ok {
+			continue
 		}
+		// a word without suggestions is still misspelled
+		suggs, _ := m.SuggestN(tok.Text, checkSuggestions)
+		misspellings = append(misspellings, Misspelling{
+			Word:        tok.Text,
+			Offset:      tok.Offset,
+			RuneOffset:  tok.RuneOffset,
+			Suggestions: suggs,
+		})
 	}
+	return misspellings
+}
 
-	min := math.MaxInt32
-	bestWord := ""
-	for word, dist := range suggMap {
-		if dist < min {
-			bestWord = word
-			min = dist
+// matchCase returns word with the casing pattern of pattern. An all
+// uppercase pattern uppercases word, a capitalized pattern capitalizes it
+// and a lowercase pattern leaves it lowercased. Any other mix is copied
+// rune by rune, runes of word past the end of pattern are lowercased.
+func matchCase(pattern, word string) string {
+	upper, lower := 0, 0
+	pr := []rune(pattern)
+	for _, r := range pr {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+	wr := []rune(strings.ToLower(word))
+	switch {
+	case upper == 0 || len(wr) == 0:
+		return string(wr)
+	case lower == 0 && upper > 1:
+		return strings.ToUpper(word)
+	case upper == 1 && unicode.IsUpper(pr[0]):
+		wr[0] = unicode.ToTitle(wr[0])
+		return string(wr)
+	}
+	for i := range wr {
+		if i < len(pr) && unicode.IsUpper(pr[i]) {
+			wr[i] = unicode.ToUpper(wr[i])
 		}
 	}
+	return string(wr)
+}
 
-	return bestWord, nil
+// bySuggestion sorts suggestions by score, then distance, then frequency,
+// then word.
+type bySuggestion []Suggestion
+
+func (s bySuggestion) Len() int      { return len(s) }
+func (s bySuggestion) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s bySuggestion) Less(i, j int) bool {
+	if s[i].Score != s[j].Score {
+		return s[i].Score > s[j].Score
+	}
+	if s[i].Distance != s[j].Distance {
+		return s[i].Distance < s[j].Distance
+	}
+	if s[i].Frequency != s[j].Frequency {
+		return s[i].Frequency > s[j].Frequency
+	}
+	return s[i].Word < s[j].Word
+}
+
+// bySimilarity sorts suggestions by similarity, then frequency, then
+// word.
+type bySimilarity []Suggestion
+
+func (s bySimilarity) Len() int      { return len(s) }
+func (s bySimilarity) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s bySimilarity) Less(i, j int) bool {
+	if s[i].Similarity != s[j].Similarity {
+		return s[i].Similarity > s[j].Similarity
+	}
+	if s[i].Frequency != s[j].Frequency {
+		return s[i].Frequency > s[j].Frequency
+	}
+	return s[i].Word < s[j].Word
 }
@@ -1,8 +1,14 @@
 package twine
 
 import (
+	"bytes"
+	"encoding/gob"
+	"errors"
 	"os"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -12,7 +18,7 @@ func TestNewMumboJumbo(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, err = NewMumboJumbo(fi, 4)
+	_, err = NewMumboJumbo(fi, WithCodeLength(4))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -25,12 +31,15 @@ var suggestTests = []struct {
 	{"caaat", "cat"},
 	{"acaaat", ""},
 	{"france", "français"},
+	{"France", "Français"},
+	{"FRANCE", "FRANÇAIS"},
+	{"Caaat", "Cat"},
 }
 
 func TestSuggest(t *testing.T) {
 	words := "filipowicz français cat"
 	r := strings.NewReader(words)
-	mj, err := NewMumboJumbo(r, 4)
+	mj, err := NewMumboJumbo(r, WithCodeLength(4))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -40,7 +49,402 @@ func TestSuggest(t *testing.T) {
 			t.Error(err)
 		}
 		if tt.out != res {
-			t.Errorf("parseLine(%s) => %s, want %s", tt.in, res, tt.out)
+			t.Errorf("Suggest(%s) => %s, want %s", tt.in, res, tt.out)
+		}
+	}
+}
+
+var suggestNTests = []struct {
+	in  string
+	n   int
+	out []Suggestion
+}{
+	{"caat", 3, []Suggestion{
+		{Word: "cat", Distance: 1, Code: "KT", Frequency: 1},
+		{Word: "cot", Distance: 2, Code: "KT", Frequency: 2},
+		{Word: "cut", Distance: 2, Code: "KT", Frequency: 1},
+	}},
+	{"caat", 0, []Suggestion{
+		{Word: "cat", Distance: 1, Code: "KT", Frequency: 1},
+		{Word: "cot", Distance: 2, Code: "KT", Frequency: 2},
+		{Word: "cut", Distance: 2, Code: "KT", Frequency: 1},
+		{Word: "kit", Distance: 3, Code: "KT", Frequency: 1},
+	}},
+	{"geof", 2, []Suggestion{
+		{Word: "jeff", Distance: 2, Code: "JF", Frequency: 1},
+		{Word: "cuff", Distance: 3, Code: "KF", Frequency: 1},
+	}},
+	{"acaaat", 2, nil},
+}
+
+func TestSuggestN(t *testing.T) {
+	words := "kit cut cot cat cot jeff cuff"
+	r := strings.NewReader(words)
+	mj, err := NewMumboJumbo(r, WithCodeLength(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range suggestNTests {
+		res, err := mj.SuggestN(tt.in, tt.n)
+		if err != nil && tt.out != nil {
+			t.Error(err)
+		}
+		for i := range res {
+			if i > 0 && res[i].Score > res[i-1].Score {
+				t.Errorf("SuggestN(%s, %d) => %+v, not ordered by score", tt.in, tt.n, res)
+			}
+			if res[i].Cost != float64(res[i].Distance) {
+				t.Errorf("SuggestN(%s, %d) => %+v, cost differs from distance", tt.in, tt.n, res[i])
+			}
+			res[i].Score, res[i].Cost, res[i].Similarity = 0, 0, 0
+		}
+		if !reflect.DeepEqual(res, tt.out) {
+			t.Errorf("SuggestN(%s, %d) => %+v, want %+v", tt.in, tt.n, res, tt.out)
+		}
+	}
+}
+
+func TestSuggestFrequency(t *testing.T) {
+	// tea is one substitution away from teh while the is two edits away,
+	// but the is far more likely to have been intended.
+	words := strings.Repeat("the ", 1000) + "tea"
+	mj, err := NewMumboJumbo(strings.NewReader(words), WithCodeLength(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := mj.Suggest("teh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != "the" {
+		t.Errorf("Suggest(teh) => %s, want the", res)
+	}
+	if res, _ := mj.Suggest("Teh"); res != "The" {
+		t.Errorf("Suggest(Teh) => %s, want The", res)
+	}
+	if mj.TotalWords != 1001 || mj.Frequencies["the"] != 1000 || mj.Frequencies["tea"] != 1 {
+		t.Errorf("TotalWords => %d, Frequencies => %v", mj.TotalWords, mj.Frequencies)
+	}
+}
+
+func TestMumboJumboWriteRead(t *testing.T) {
+	fi, err := os.Open("big.txt.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fi.Close()
+	mj, err := NewMumboJumbo(fi, WithCodeLength(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	n, err := mj.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo => %d bytes, wrote %d", n, buf.Len())
+	}
+	loaded, err := ReadMumboJumbo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.CodeLength != mj.CodeLength || loaded.TotalWords != mj.TotalWords {
+		t.Errorf("ReadMumboJumbo => CodeLength %d TotalWords %d, want %d %d",
+			loaded.CodeLength, loaded.TotalWords, mj.CodeLength, mj.TotalWords)
+	}
+	if !reflect.DeepEqual(loaded.Metaphones, mj.Metaphones) {
+		t.Error("ReadMumboJumbo => Metaphones differ")
+	}
+	if !reflect.DeepEqual(loaded.Frequencies, mj.Frequencies) {
+		t.Error("ReadMumboJumbo => Frequencies differ")
+	}
+	for _, in := range []string{"caaat", "teh", "speling"} {
+		want, _ := mj.Suggest(in)
+		res, _ := loaded.Suggest(in)
+		if res != want {
+			t.Errorf("Suggest(%s) => %s, want %s", in, res, want)
+		}
+	}
+}
+
+func TestMumboJumboWriteReadEdits(t *testing.T) {
+	mj, err := NewMumboJumbo(strings.NewReader("cat"), WithStrategy(StrategyEdits))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := mj.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ReadMumboJumbo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.deletes, mj.deletes) {
+		t.Errorf("ReadMumboJumbo => deletes %v, want %v", loaded.deletes, mj.deletes)
+	}
+	if res, _ := loaded.Suggest("cst"); res != "cat" {
+		t.Errorf("Suggest(cst) => %s, want cat", res)
+	}
+}
+
+func TestReadMumboJumboVersion(t *testing.T) {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	if err := enc.Encode(indexHeader{Version: indexVersion + 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadMumboJumbo(&buf); !errors.Is(err, ErrIndexVersion) {
+		t.Errorf("ReadMumboJumbo with unknown version => %v, want %v", err, ErrIndexVersion)
+	}
+	if _, err := ReadMumboJumbo(strings.NewReader("not an index")); err == nil {
+		t.Error("ReadMumboJumbo with garbage => nil error")
+	}
+}
+
+func TestMumboJumboAddRemove(t *testing.T) {
+	mj, err := NewMumboJumbo(strings.NewReader("cat"), WithCodeLength(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mj.Contains("twine") {
+		t.Error("Contains(twine) => true, want false")
+	}
+	if res, _ := mj.Suggest("twyne"); res != "" {
+		t.Errorf("Suggest(twyne) => %s, want none", res)
+	}
+
+	mj.AddWord("Twine")
+	if !mj.Contains("twine") || !mj.Contains("TWINE") {
+		t.Error("Contains(twine) => false, want true")
+	}
+	if res, err := mj.Suggest("twyne"); err != nil || res != "twine" {
+		t.Errorf("Suggest(twyne) => %s %v, want twine", res, err)
+	}
+
+	err = mj.AddReader(strings.NewReader("golang gopher, golang"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mj.Frequencies["golang"] != 2 || mj.TotalWords != 5 {
+		t.Errorf("AddReader => Frequencies %v TotalWords %d", mj.Frequencies, mj.TotalWords)
+	}
+
+	if err := mj.RemoveWord("golang"); err != nil {
+		t.Fatal(err)
+	}
+	if mj.Contains("golang") || mj.TotalWords != 3 {
+		t.Errorf("RemoveWord(golang) => Contains %v TotalWords %d", mj.Contains("golang"), mj.TotalWords)
+	}
+	if res, _ := mj.Suggest("golag"); res != "" {
+		t.Errorf("Suggest(golag) => %s, want none", res)
+	}
+	if err := mj.RemoveWord("golang"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("RemoveWord(golang) twice => %v, want %v", err, ErrNotFound)
+	}
+
+	// words are split and normalized the same way when added, looked up
+	// and removed
+	mj.AddWord("Golang!")
+	if !mj.Contains("Golang!") || !mj.Contains("gopher golang") || mj.Contains("golang rust") || mj.Contains("!") {
+		t.Error("Contains(Golang!) => false, want true")
+	}
+	if err := mj.RemoveWord("golang rust"); !errors.Is(err, ErrNotFound) || !mj.Contains("golang") {
+		t.Errorf("RemoveWord(golang rust) => %v, want %v and golang kept", err, ErrNotFound)
+	}
+	if err := mj.RemoveWord("Golang!"); err != nil || mj.Contains("golang") {
+		t.Errorf("RemoveWord(Golang!) => %v, want golang removed", err)
+	}
+}
+
+func TestMumboJumboConcurrentAdd(t *testing.T) {
+	mj, err := NewMumboJumbo(strings.NewReader("cat"), WithCodeLength(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				mj.AddWord("kitten")
+				mj.Suggest("kiten")
+				mj.Contains("kitten")
+			}
+		}()
+	}
+	wg.Wait()
+	if mj.Frequencies["kitten"] != 800 {
+		t.Errorf("Frequencies[kitten] => %d, want 800", mj.Frequencies["kitten"])
+	}
+}
+
+var checkTests = []struct {
+	in  string
+	out []Misspelling
+}{
+	{"", []Misspelling{}},
+	{"the cat", []Misspelling{}},
+	{"The caat, Schübler!", []Misspelling{
+		{Word: "caat", Offset: 4, RuneOffset: 4, Suggestions: []Suggestion{{Word: "cat"}}},
+		{Word: "Schübler", Offset: 10, RuneOffset: 10, Suggestions: nil},
+	}},
+	{"THE CAAT", []Misspelling{
+		{Word: "CAAT", Offset: 4, RuneOffset: 4, Suggestions: []Suggestion{{Word: "CAT"}}},
+	}},
+	{"ça caat", []Misspelling{
+		{Word: "ça", Offset: 0, RuneOffset: 0, Suggestions: nil},
+		{Word: "caat", Offset: 4, RuneOffset: 3, Suggestions: []Suggestion{{Word: "cat"}}},
+	}},
+}
+
+func TestCheck(t *testing.T) {
+	mj, err := NewMumboJumbo(strings.NewReader("the cat"), WithCodeLength(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range checkTests {
+		res := mj.Check(tt.in)
+		for i := range res {
+			for j := range res[i].Suggestions {
+				res[i].Suggestions[j] = Suggestion{Word: res[i].Suggestions[j].Word}
+			}
+		}
+		if !reflect.DeepEqual(res, tt.out) {
+			t.Errorf("Check(%s) => %+v, want %+v", tt.in, res, tt.out)
+		}
+		for _, ms := range res {
+			if tt.in[ms.Offset:ms.Offset+len(ms.Word)] != ms.Word {
+				t.Errorf("Check(%s) => offset %d does not point at %s", tt.in, ms.Offset, ms.Word)
+			}
+		}
+	}
+}
+
+var tokenizeTests = []struct {
+	in  string
+	out []Token
+}{
+	{"", []Token{}},
+	{"a b", []Token{{"a", 0, 0}, {"b", 2, 2}}},
+	{"Schübler d.? français", []Token{{"Schübler", 0, 0}, {"d", 10, 9}, {"français", 14, 13}}},
+	{"records--of", []Token{{"records", 0, 0}, {"of", 9, 9}}},
+	{"cafe\u0301 \u0301x", []Token{{"cafe\u0301", 0, 0}, {"x", 9, 7}}},
+}
+
+func TestTokenize(t *testing.T) {
+	for _, tt := range tokenizeTests {
+		res := Tokenize(tt.in)
+		if !reflect.DeepEqual(res, tt.out) {
+			t.Errorf("tokenize(%s) => %v, want %v", tt.in, res, tt.out)
+		}
+	}
+}
+
+var matchCaseTests = []struct {
+	pattern string
+	word    string
+	out     string
+}{
+	{"teh", "the", "the"},
+	{"Teh", "the", "The"},
+	{"TEH", "the", "THE"},
+	{"FRANCE", "français", "FRANÇAIS"},
+	{"T", "a", "A"},
+	{"iPhnoe", "iphone", "iPhone"},
+	{"mcDonld", "mcdonalds", "mcDonalds"},
+	{"abc", "", ""},
+	{"", "Cat", "cat"},
+}
+
+func TestMatchCase(t *testing.T) {
+	for _, tt := range matchCaseTests {
+		res := matchCase(tt.pattern, tt.word)
+		if res != tt.out {
+			t.Errorf("matchCase(%s, %s) => %s, want %s", tt.pattern, tt.word, res, tt.out)
+		}
+	}
+}
+
+var strategyTests = []struct {
+	in       string
+	strategy Strategy
+	out      string
+}{
+	{"caaat", StrategyMetaphone, "cat"},
+	{"caaat", StrategyEdits, "cat"},
+	{"caaat", StrategyBoth, "cat"},
+	{"cst", StrategyMetaphone, ""},
+	{"cst", StrategyEdits, "cat"},
+	{"cst", StrategyBoth, "cat"},
+	{"france", StrategyMetaphone, "français"},
+	{"france", StrategyEdits, ""},
+	{"acaaat", StrategyBoth, ""},
+	{"cat", StrategyEdits, "cat"},
+}
+
+func TestSuggestStrategy(t *testing.T) {
+	mj, err := NewMumboJumbo(strings.NewReader("filipowicz français cat"), WithCodeLength(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range strategyTests {
+		mj.Strategy = tt.strategy
+		res, _ := mj.Suggest(tt.in)
+		if res != tt.out {
+			t.Errorf("Suggest(%s) with strategy %d => %s, want %s", tt.in, tt.strategy, res, tt.out)
+		}
+	}
+}
+
+var suggestErrorTests = []struct {
+	in  string
+	err error
+}{
+	{"", ErrEmptyInput},
+	{"xyzzyx", ErrNoSuggestion},
+	{"acaaat", ErrNoSuggestion},
+}
+
+func TestSuggestErrors(t *testing.T) {
+	mj, err := NewMumboJumbo(strings.NewReader("cat"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range suggestErrorTests {
+		if _, err := mj.Suggest(tt.in); !errors.Is(err, tt.err) {
+			t.Errorf("Suggest(%s) => %v, want %v", tt.in, err, tt.err)
+		}
+	}
+}
+
+var deletionsTests = []struct {
+	in        string
+	depth     int
+	graphemes bool
+	out       []string
+}{
+	{"", 2, false, []string{""}},
+	{"ab", 0, false, []string{"ab"}},
+	{"ab", 1, false, []string{"a", "ab", "b"}},
+	{"ab", 2, false, []string{"", "a", "ab", "b"}},
+	{"aab", 1, false, []string{"aa", "aab", "ab"}},
+	{"çab", 2, false, []string{"a", "ab", "b", "ç", "ça", "çab", "çb"}},
+	{"e\u0301a", 1, false, []string{"ea", "e\u0301", "e\u0301a", "\u0301a"}},
+	{"e\u0301a", 1, true, []string{"a", "e\u0301", "e\u0301a"}},
+}
+
+func TestDeletions(t *testing.T) {
+	for _, tt := range deletionsTests {
+		res := []string{}
+		for del := range deletions(tt.in, tt.depth, tt.graphemes) {
+			res = append(res, del)
+		}
+		sort.Strings(res)
+		if !reflect.DeepEqual(res, tt.out) {
+			t.Errorf("deletions(%s, %d, %v) => %q, want %q", tt.in, tt.depth, tt.graphemes, res, tt.out)
 		}
 	}
 }
@@ -0,0 +1,210 @@
+package twine
+
+import (
+	"strings"
+	"sync"
+)
+
+// Option configures a MumboJumbo.
+type Option func(*MumboJumbo)
+
+// Tokenizer splits text into words.
+type Tokenizer func(text string) []Token
+
+// Token is a word found in a text along with where it starts.
+type Token struct {
+	Text       string
+	Offset     int // byte offset of the word in the text
+	RuneOffset int // rune offset of the word in the text
+}
+
+// DistanceFunc measures the edit distance between two strings.
+type DistanceFunc func(source, target string) int
+
+// WithCodeLength sets the length of the double metaphone codes words are
+// indexed under, the default is 4.
+func WithCodeLength(codeLength int) Option {
+	return func(m *MumboJumbo) {
+		m.CodeLength = codeLength
+	}
+}
+
+// WithStrategy sets how candidate corrections are found, the default is
+// StrategyMetaphone.
+func WithStrategy(strategy Strategy) Option {
+	return func(m *MumboJumbo) {
+		m.Strategy = strategy
+	}
+}
+
+// WithMaxEdits sets how many deletions, substitutions or insertions away
+// from the input StrategyEdits and StrategyBoth look for words, the
+// default is 2. Typos such as "acaaat" for "cat" need 3. Each extra edit
+// multiplies the size of the deletion index built from the dictionary
+// by about the length of its words.
+func WithMaxEdits(maxEdits int) Option {
+	return func(m *MumboJumbo) {
+		m.maxEdits = maxEdits
+	}
+}
+
+// WithTokenizer sets how the corpus and checked text are split into
+// words, the default is Tokenize.
+func WithTokenizer(tokenizer Tokenizer) Option {
+	return func(m *MumboJumbo) {
+		m.tokenizer = tokenizer
+	}
+}
+
+// WithNormalizer sets how words are normalized before being indexed or
+// looked up, the default is strings.ToLower.
+func WithNormalizer(normalizer func(string) string) Option {
+	return func(m *MumboJumbo) {
+		m.normalizer = normalizer
+	}
+}
+
+// WithUnicode normalizes words before the normalizer when they are read
+// and looked up, and when u.Graphemes is set and no distance is
+// configured measures distances between grapheme clusters.
+func WithUnicode(u Unicode) Option {
+	return func(m *MumboJumbo) {
+		m.unicode = u
+	}
+}
+
+// WithDistance sets the distance used to rank suggestions, the default
+// is LevenshteinDistance.
+func WithDistance(distance DistanceFunc) Option {
+	return func(m *MumboJumbo) {
+		m.distance = distance
+	}
+}
+
+// WithMetric ranks suggestions by their metric distance from the input
+// rather than their edit distance, the metric standing in for the number
+// of edits in the noisy channel score. Distances of a NormalizedMetric
+// are multiplied by the length in runes of the longer string, so that
+// SimilarityMetric(LevenshteinSimilarity) ranks like the edit distance
+// and a Jaro-Winkler distance of 0.1 between six letter words weighs as
+// 0.6 edits. Distance limits still apply to the edit distance. Metrics
+// can be looked up by name with LookupMetric.
+func WithMetric(metric Metric) Option {
+	return func(m *MumboJumbo) {
+		m.metric = metric
+	}
+}
+
+// WithCostModel ranks suggestions by their WeightedLevenshtein cost
+// from the input rather than their distance. Distance limits still apply
+// to the distance.
+func WithCostModel(costs CostModel) Option {
+	return WithMetric(costs)
+}
+
+// WithKeyboard ranks suggestions by the cost of typing them on layout,
+// substitutions of neighbouring keys being cheaper than others.
+func WithKeyboard(layout *KeyboardLayout) Option {
+	return WithCostModel(layout.CostModel())
+}
+
+// WithMaxDistance drops suggestions further than maxDistance from the
+// input, Suggest then returns a *DistanceError rather than an unrelated
+// word. Zero, the default, keeps all of them.
+func WithMaxDistance(maxDistance int) Option {
+	return func(m *MumboJumbo) {
+		m.maxDistance = maxDistance
+	}
+}
+
+// WithMaxRelativeDistance drops suggestions further from the input than
+// ratio times its length in runes, so 0.34 allows one edit in a three
+// letter word and three in a nine letter one. When combined with
+// WithMaxDistance the smaller limit applies. Zero, the default, keeps
+// all suggestions.
+func WithMaxRelativeDistance(ratio float64) Option {
+	return func(m *MumboJumbo) {
+		m.maxRelative = ratio
+	}
+}
+
+// WithRanking sets how suggestions are ordered, the default is
+// RankNoisyChannel.
+func WithRanking(ranking Ranking) Option {
+	return func(m *MumboJumbo) {
+		m.ranking = ranking
+	}
+}
+
+// WithMinSimilarity drops suggestions whose Similarity to the input is
+// below minSimilarity, e.g. 0.8 for one edit in five runes, which works
+// as a single threshold across short and long inputs.
+func WithMinSimilarity(minSimilarity float64) Option {
+	return func(m *MumboJumbo) {
+		m.minSimilarity = minSimilarity
+	}
+}
+
+// WithMinFrequency drops suggestions seen fewer than minFrequency times
+// in the corpus.
+func WithMinFrequency(minFrequency int) Option {
+	return func(m *MumboJumbo) {
+		m.minFrequency = minFrequency
+	}
+}
+
+// WithMinWordLength ignores words shorter than minWordLength runes both
+// in the corpus and in checked text.
+func WithMinWordLength(minWordLength int) Option {
+	return func(m *MumboJumbo) {
+		m.minWordLength = minWordLength
+	}
+}
+
+// WithStopWords sets words that are never indexed, suggested or flagged
+// by Check.
+func WithStopWords(words ...string) Option {
+	return func(m *MumboJumbo) {
+		for _, word := range words {
+			m.stopWords[word] = struct{}{}
+		}
+	}
+}
+
+// WithGzipDetection sets whether readers starting with the gzip magic
+// number are decompressed, the default is true.
+func WithGzipDetection(detect bool) Option {
+	return func(m *MumboJumbo) {
+		m.detectGzip = detect
+	}
+}
+
+// newMumboJumbo returns an empty MumboJumbo configured with opts.
+func newMumboJumbo(opts ...Option) *MumboJumbo {
+	mj := &MumboJumbo{
+		Metaphones:  map[string]map[string]struct{}{},
+		Frequencies: map[string]int{},
+		CodeLength:  4,
+		tokenizer:   Tokenize,
+		normalizer:  strings.ToLower,
+		stopWords:   map[string]struct{}{},
+		detectGzip:  true,
+		maxEdits:    2,
+		mu:          &sync.Mutex{},
+	}
+	for _, opt := range opts {
+		opt(mj)
+	}
+	// stop words are compared after normalization
+	stopWords := make(map[string]struct{}, len(mj.stopWords))
+	for word := range mj.stopWords {
+		stopWords[mj.normalize(word)] = struct{}{}
+	}
+	mj.stopWords = stopWords
+	// the deletion index is built as the corpus is read when it will be
+	// used, otherwise on the first suggestion needing it
+	if mj.Strategy != StrategyMetaphone {
+		mj.deletes = map[string][]string{}
+	}
+	return mj
+}
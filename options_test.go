@@ -0,0 +1,440 @@
+package twine
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewMumboJumboDefaults(t *testing.T) {
+	mj, err := NewMumboJumbo(strings.NewReader("cat"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mj.CodeLength != 4 || mj.Strategy != StrategyMetaphone {
+		t.Errorf("NewMumboJumbo => CodeLength %d Strategy %d, want 4 %d", mj.CodeLength, mj.Strategy, StrategyMetaphone)
+	}
+	// the zero values are the defaults
+	var mj0 MumboJumbo
+	if mj0.Strategy != StrategyMetaphone || mj0.ranking != RankNoisyChannel {
+		t.Errorf("MumboJumbo{} => Strategy %d ranking %d, want %d %d", mj0.Strategy, mj0.ranking, StrategyMetaphone, RankNoisyChannel)
+	}
+}
+
+func TestWithCodeLength(t *testing.T) {
+	mj, err := NewMumboJumbo(strings.NewReader("cambrillo"), WithCodeLength(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := mj.Metaphones["KM"]; !ok {
+		t.Errorf("WithCodeLength(2) => Metaphones %v, want KM", mj.Metaphones)
+	}
+}
+
+func TestWithStrategy(t *testing.T) {
+	mj, err := NewMumboJumbo(strings.NewReader("cat"), WithStrategy(StrategyMetaphone))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := mj.Suggest("cst"); res != "" {
+		t.Errorf("WithStrategy(StrategyMetaphone) Suggest(cst) => %s, want none", res)
+	}
+	mj, err = NewMumboJumbo(strings.NewReader("cat"), WithStrategy(StrategyEdits))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := mj.Suggest("cst"); res != "cat" {
+		t.Errorf("WithStrategy(StrategyEdits) Suggest(cst) => %s, want cat", res)
+	}
+	mj.AddWord("cut")
+	mj.AddWord("cut")
+	if res, _ := mj.Suggest("cst"); res != "cut" {
+		t.Errorf("WithStrategy(StrategyEdits) AddWord(cut) Suggest(cst) => %s, want cut", res)
+	}
+	if err := mj.RemoveWord("cut"); err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := mj.Suggest("cst"); res != "cat" {
+		t.Errorf("WithStrategy(StrategyEdits) RemoveWord(cut) Suggest(cst) => %s, want cat", res)
+	}
+}
+
+var maxEditsTests = []struct {
+	in       string
+	maxEdits int
+	out      string
+}{
+	{"acaaat", 2, ""},
+	{"acaaat", 3, "cat"},
+	{"cst", 1, "cat"},
+	{"cstt", 1, ""},
+	{"cat", 0, "cat"},
+	{"cst", 0, ""},
+}
+
+func TestWithMaxEdits(t *testing.T) {
+	for _, tt := range maxEditsTests {
+		mj, err := NewMumboJumbo(strings.NewReader("filipowicz cat"),
+			WithStrategy(StrategyEdits), WithMaxEdits(tt.maxEdits))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res, _ := mj.Suggest(tt.in); res != tt.out {
+			t.Errorf("WithMaxEdits(%d) Suggest(%s) => %s, want %s", tt.maxEdits, tt.in, res, tt.out)
+		}
+	}
+}
+
+func TestWithTokenizer(t *testing.T) {
+	// split on whitespace only so digits and punctuation are kept
+	fields := func(text string) []Token {
+		tokens := []Token{}
+		for _, f := range strings.Fields(text) {
+			tokens = append(tokens, Token{Text: f})
+		}
+		return tokens
+	}
+	mj, err := NewMumboJumbo(strings.NewReader("r2d2 c-3po"), WithTokenizer(fields))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mj.Contains("r2d2") || !mj.Contains("c-3po") {
+		t.Errorf("WithTokenizer => Frequencies %v", mj.Frequencies)
+	}
+}
+
+func TestWithNormalizer(t *testing.T) {
+	mj, err := NewMumboJumbo(strings.NewReader("Go go"), WithNormalizer(strings.TrimSpace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mj.Frequencies["Go"] != 1 || mj.Frequencies["go"] != 1 {
+		t.Errorf("WithNormalizer => Frequencies %v", mj.Frequencies)
+	}
+}
+
+func TestWithDistance(t *testing.T) {
+	constant := func(source, target string) int { return 7 }
+	mj, err := NewMumboJumbo(strings.NewReader("cat"), WithDistance(constant))
+	if err != nil {
+		t.Fatal(err)
+	}
+	suggs, err := mj.SuggestN("caat", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suggs[0].Distance != 7 {
+		t.Errorf("WithDistance => Distance %d, want 7", suggs[0].Distance)
+	}
+}
+
+func TestWithDistanceTransposition(t *testing.T) {
+	// with transpositions costing one edit the is as close to teh as tea
+	words := strings.Repeat("the ", 10) + strings.Repeat("tea ", 10)
+	for _, distance := range []DistanceFunc{OSADistance, DamerauLevenshteinDistance} {
+		mj, err := NewMumboJumbo(strings.NewReader(words), WithDistance(distance))
+		if err != nil {
+			t.Fatal(err)
+		}
+		suggs, err := mj.SuggestN("teh", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if suggs[0].Distance != 1 || suggs[1].Distance != 1 {
+			t.Errorf("WithDistance SuggestN(teh) => %+v, want distance 1", suggs)
+		}
+	}
+}
+
+func TestWithKeyboard(t *testing.T) {
+	words := "cat cut cut"
+	mj, err := NewMumboJumbo(strings.NewReader(words), WithStrategy(StrategyBoth))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := mj.Suggest("cst"); res != "cut" {
+		t.Errorf("Suggest(cst) => %s, want cut", res)
+	}
+	mj, err = NewMumboJumbo(strings.NewReader(words), WithStrategy(StrategyBoth), WithKeyboard(QWERTY))
+	if err != nil {
+		t.Fatal(err)
+	}
+	suggs, err := mj.SuggestN("cst", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suggs[0].Word != "cat" || suggs[0].Cost != 0.5 || suggs[1].Word != "cut" || suggs[1].Cost != 1 {
+		t.Errorf("WithKeyboard(QWERTY) SuggestN(cst) => %+v, want cat then cut", suggs)
+	}
+}
+
+func TestWithUnicode(t *testing.T) {
+	words := "cafe\u0301 cafe\u0301 ﬁne"
+	mj, err := NewMumboJumbo(strings.NewReader(words), WithUnicode(Unicode{Form: FormNFKC}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mj.Frequencies["caf\u00e9"] != 2 || mj.Frequencies["fine"] != 1 {
+		t.Errorf("WithUnicode(NFKC) Frequencies => %v, want café and fine", mj.Frequencies)
+	}
+	if !mj.Contains("cafe\u0301") || !mj.Contains("caf\u00e9") {
+		t.Errorf("WithUnicode(NFKC) Contains(café) => false, want true")
+	}
+
+	mj, err = NewMumboJumbo(strings.NewReader(words), WithUnicode(Unicode{FoldDiacritics: true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mj.Contains("CAFÉ") || !mj.Contains("cafe") {
+		t.Errorf("WithUnicode(FoldDiacritics) Contains(cafe) => false, want true")
+	}
+
+	mj, err = NewMumboJumbo(strings.NewReader(words), WithMaxDistance(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mj.Suggest("caf"); !errors.Is(err, ErrNoSuggestion) {
+		t.Errorf("Suggest(caf) => %v, want %v", err, ErrNoSuggestion)
+	}
+	mj, err = NewMumboJumbo(strings.NewReader(words), WithMaxDistance(1), WithUnicode(Unicode{Graphemes: true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, err := mj.Suggest("caf"); err != nil || res != "cafe\u0301" {
+		t.Errorf("WithUnicode(Graphemes) Suggest(caf) => %q %v, want café", res, err)
+	}
+	// caf is one grapheme short of the four in café
+	res, err := mj.SuggestN("caf", 1)
+	if err != nil || res[0].Similarity != 0.75 {
+		t.Errorf("WithUnicode(Graphemes) SuggestN(caf) => %v %v, want similarity 0.75", res, err)
+	}
+	mj, err = NewMumboJumbo(strings.NewReader(words), WithMinSimilarity(0.8), WithUnicode(Unicode{Graphemes: true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mj.Suggest("caf"); !errors.Is(err, ErrNoSuggestion) {
+		t.Errorf("WithUnicode(Graphemes) WithMinSimilarity(0.8) Suggest(caf) => %v, want %v", err, ErrNoSuggestion)
+	}
+	mj, err = NewMumboJumbo(strings.NewReader(words), WithStrategy(StrategyEdits), WithMaxEdits(1), WithUnicode(Unicode{Graphemes: true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, err := mj.Suggest("caf"); err != nil || res != "cafe\u0301" {
+		t.Errorf("WithUnicode(Graphemes) WithStrategy(StrategyEdits) Suggest(caf) => %q %v, want café", res, err)
+	}
+}
+
+func TestWithMetric(t *testing.T) {
+	// mart is more common but further from marhta than martha
+	words := "martha mart mart mart"
+	jw, err := LookupMetric("jaro-winkler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mj, err := NewMumboJumbo(strings.NewReader(words), WithStrategy(StrategyBoth), WithMetric(jw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	suggs, err := mj.SuggestN("marhta", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggs) != 2 || suggs[0].Word != "martha" || suggs[1].Word != "mart" {
+		t.Fatalf("WithMetric(jaro-winkler) SuggestN(marhta) => %+v, want martha then mart", suggs)
+	}
+	// normalized distances are weighed as edits of the longer word
+	if cost := 6 * jw.Distance("marhta", "martha"); suggs[0].Cost != cost {
+		t.Errorf("WithMetric(jaro-winkler) SuggestN(marhta) Cost => %v, want %v", suggs[0].Cost, cost)
+	}
+
+	// a normalized Levenshtein distance ranks like the edit distance
+	lev, err := LookupMetric("levenshtein-similarity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := NewMumboJumbo(strings.NewReader(words), WithStrategy(StrategyBoth))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mj, err = NewMumboJumbo(strings.NewReader(words), WithStrategy(StrategyBoth), WithMetric(lev))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, in := range []string{"marhta", "mrt", "marth", "mrtha"} {
+		want, _ := plain.SuggestN(in, 0)
+		res, _ := mj.SuggestN(in, 0)
+		for i := range res {
+			res[i].Cost = math.Round(res[i].Cost*1e9) / 1e9
+			res[i].Score = want[i].Score
+		}
+		if !reflect.DeepEqual(res, want) {
+			t.Errorf("WithMetric(levenshtein-similarity) SuggestN(%s) => %+v, want %+v", in, res, want)
+		}
+	}
+}
+
+func TestWithMaxDistance(t *testing.T) {
+	mj, err := NewMumboJumbo(strings.NewReader("cat"), WithMaxDistance(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := mj.Suggest("caat"); res != "cat" {
+		t.Errorf("WithMaxDistance(1) Suggest(caat) => %s, want cat", res)
+	}
+	res, err := mj.Suggest("caaat")
+	derr, ok := err.(*DistanceError)
+	if !ok {
+		t.Fatalf("WithMaxDistance(1) Suggest(caaat) => %s %v, want *DistanceError", res, err)
+	}
+	if !errors.Is(err, ErrNoSuggestion) {
+		t.Errorf("WithMaxDistance(1) Suggest(caaat) => %v, want %v", err, ErrNoSuggestion)
+	}
+	want := DistanceError{Input: "caaat", Word: "cat", Distance: 2, MaxDistance: 1}
+	if *derr != want {
+		t.Errorf("WithMaxDistance(1) Suggest(caaat) => %+v, want %+v", *derr, want)
+	}
+}
+
+var maxRelativeDistanceTests = []struct {
+	in       string
+	ratio    float64
+	distance int
+	out      string
+}{
+	{"caat", 0.25, 0, "cat"},
+	{"caat", 0.2, 0, ""},
+	{"cst", 0.34, 0, "cat"},
+	{"cstt", 0.34, 0, ""},
+	{"cstt", 0.5, 0, "cat"},
+	{"cstt", 0.5, 1, ""},
+	// 0.58 * 50 is 28.999999999999996 in floating point
+	{"a" + strings.Repeat("e", 29) + strings.Repeat("a", 20), 0.58, 0, strings.Repeat("a", 50)},
+	{"a" + strings.Repeat("e", 29) + strings.Repeat("a", 20), 0.56, 0, ""},
+}
+
+func TestWithMaxRelativeDistance(t *testing.T) {
+	for _, tt := range maxRelativeDistanceTests {
+		mj, err := NewMumboJumbo(strings.NewReader("cat "+strings.Repeat("a", 50)), WithStrategy(StrategyBoth),
+			WithMaxRelativeDistance(tt.ratio), WithMaxDistance(tt.distance))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := mj.Suggest(tt.in)
+		if res != tt.out {
+			t.Errorf("WithMaxRelativeDistance(%v) Suggest(%s) => %s, want %s", tt.ratio, tt.in, res, tt.out)
+		}
+		if _, ok := err.(*DistanceError); tt.out == "" && !ok {
+			t.Errorf("WithMaxRelativeDistance(%v) Suggest(%s) => %v, want *DistanceError", tt.ratio, tt.in, err)
+		}
+	}
+}
+
+func TestWithRanking(t *testing.T) {
+	// cut is common but further from caet than cat
+	words := "cat" + strings.Repeat(" cut", 1000)
+	mj, err := NewMumboJumbo(strings.NewReader(words))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := mj.Suggest("caet"); res != "cut" {
+		t.Errorf("Suggest(caet) => %s, want cut", res)
+	}
+	mj, err = NewMumboJumbo(strings.NewReader(words), WithRanking(RankSimilarity))
+	if err != nil {
+		t.Fatal(err)
+	}
+	suggs, err := mj.SuggestN("caet", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suggs[0].Word != "cat" || suggs[0].Similarity != 0.75 || suggs[1].Word != "cut" {
+		t.Errorf("WithRanking(RankSimilarity) SuggestN(caet) => %+v, want cat then cut", suggs)
+	}
+}
+
+var minSimilarityTests = []struct {
+	in  string
+	out string
+}{
+	{"cat", "cat"},
+	{"caat", ""},
+	{"elephent", "elephant"},
+	{"elefent", ""},
+}
+
+func TestWithMinSimilarity(t *testing.T) {
+	mj, err := NewMumboJumbo(strings.NewReader("cat elephant"), WithMinSimilarity(0.8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range minSimilarityTests {
+		res, err := mj.Suggest(tt.in)
+		if res != tt.out {
+			t.Errorf("WithMinSimilarity(0.8) Suggest(%s) => %s, want %s", tt.in, res, tt.out)
+		}
+		if tt.out == "" && !errors.Is(err, ErrNoSuggestion) {
+			t.Errorf("WithMinSimilarity(0.8) Suggest(%s) => %v, want %v", tt.in, err, ErrNoSuggestion)
+		}
+	}
+}
+
+func TestWithMinFrequency(t *testing.T) {
+	mj, err := NewMumboJumbo(strings.NewReader("cat cut cut"), WithMinFrequency(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := mj.Suggest("cat"); res != "cut" {
+		t.Errorf("WithMinFrequency(2) Suggest(cat) => %s, want cut", res)
+	}
+}
+
+func TestWithMinWordLength(t *testing.T) {
+	mj, err := NewMumboJumbo(strings.NewReader("a an ant"), WithMinWordLength(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mj.Contains("a") || !mj.Contains("an") {
+		t.Errorf("WithMinWordLength(2) => Frequencies %v", mj.Frequencies)
+	}
+	if res := mj.Check("b an"); len(res) != 0 {
+		t.Errorf("WithMinWordLength(2) Check(b an) => %v, want none", res)
+	}
+}
+
+func TestWithStopWords(t *testing.T) {
+	mj, err := NewMumboJumbo(strings.NewReader("the cat"), WithStopWords("The", "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mj.Contains("the") {
+		t.Errorf("WithStopWords => Frequencies %v", mj.Frequencies)
+	}
+	if res := mj.Check("A cat"); len(res) != 0 {
+		t.Errorf("WithStopWords Check(A cat) => %v, want none", res)
+	}
+}
+
+func TestWithGzipDetection(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte("cat"))
+	zw.Close()
+	mj, err := NewMumboJumbo(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mj.Contains("cat") {
+		t.Errorf("gzip detection => Frequencies %v, want cat", mj.Frequencies)
+	}
+	mj, err = NewMumboJumbo(bytes.NewReader(buf.Bytes()), WithGzipDetection(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reflect.DeepEqual(mj.Frequencies, map[string]int{"cat": 1}) {
+		t.Errorf("WithGzipDetection(false) => Frequencies %v, want compressed bytes", mj.Frequencies)
+	}
+}
@@ -0,0 +1,152 @@
+package twine
+
+import "math"
+
+// Runes padding strings when QGram.Pad is set, they mark q-grams at the
+// start and at the end of a string.
+const (
+	qgramStart = '\u0002'
+	qgramEnd   = '\u0003'
+)
+
+// QGram splits strings into q-grams, the substrings of Q runes.
+// With Pad set strings are extended with Q-1 start and end markers so
+// that their first and last runes are part of as many q-grams as the
+// others. Without padding a non empty string shorter than Q is its own
+// single q-gram, so only the empty string has an empty profile.
+type QGram struct {
+	Q   int
+	Pad bool
+}
+
+// QGramProfile counts the occurrences of each q-gram of a string.
+// Profiles can be computed once and kept in an index to compare strings
+// cheaply before computing a more expensive distance.
+type QGramProfile map[string]int
+
+// Profile returns the q-gram profile of s. A Q below 1 is treated as 1.
+func (g QGram) Profile(s string) QGramProfile {
+	q := g.Q
+	if q < 1 {
+		q = 1
+	}
+	runes := []rune(s)
+	if g.Pad {
+		padded := make([]rune, 0, len(runes)+2*(q-1))
+		for i := 0; i < q-1; i++ {
+			padded = append(padded, qgramStart)
+		}
+		padded = append(padded, runes...)
+		for i := 0; i < q-1; i++ {
+			padded = append(padded, qgramEnd)
+		}
+		runes = padded
+	}
+
+	p := QGramProfile{}
+	if len(runes) > 0 && len(runes) < q {
+		p[string(runes)]++
+	}
+	for i := 0; i+q <= len(runes); i++ {
+		p[string(runes[i:i+q])]++
+	}
+	return p
+}
+
+// Jaccard returns the Jaccard similarity of the q-gram sets of two
+// strings, see QGramProfile.Jaccard.
+func (g QGram) Jaccard(source string, target string) float64 {
+	return g.Profile(source).Jaccard(g.Profile(target))
+}
+
+// Dice returns the Sørensen-Dice coefficient of the q-gram sets of two
+// strings, see QGramProfile.Dice.
+func (g QGram) Dice(source string, target string) float64 {
+	return g.Profile(source).Dice(g.Profile(target))
+}
+
+// Cosine returns the cosine similarity of the q-gram profiles of two
+// strings, see QGramProfile.Cosine.
+func (g QGram) Cosine(source string, target string) float64 {
+	return g.Profile(source).Cosine(g.Profile(target))
+}
+
+// Distance returns the q-gram distance between two strings, see
+// QGramProfile.Distance.
+func (g QGram) Distance(source string, target string) int {
+	return g.Profile(source).Distance(g.Profile(target))
+}
+
+// common returns the number of distinct q-grams in both profiles.
+func (p QGramProfile) common(o QGramProfile) int {
+	if len(o) < len(p) {
+		p, o = o, p
+	}
+	n := 0
+	for gram := range p {
+		if _, ok := o[gram]; ok {
+			n++
+		}
+	}
+	return n
+}
+
+// Jaccard returns |A ∩ B| / |A ∪ B| for the sets of distinct q-grams of
+// both profiles, between 0 and 1. Two empty profiles, which only empty
+// strings have, are identical.
+func (p QGramProfile) Jaccard(o QGramProfile) float64 {
+	if len(p) == 0 && len(o) == 0 {
+		return 1
+	}
+	n := p.common(o)
+	return float64(n) / float64(len(p)+len(o)-n)
+}
+
+// Dice returns the Sørensen-Dice coefficient 2|A ∩ B| / (|A| + |B|) for
+// the sets of distinct q-grams of both profiles, between 0 and 1. Two
+// empty profiles, which only empty strings have, are identical.
+func (p QGramProfile) Dice(o QGramProfile) float64 {
+	if len(p) == 0 && len(o) == 0 {
+		return 1
+	}
+	return 2 * float64(p.common(o)) / float64(len(p)+len(o))
+}
+
+// Cosine returns the cosine of the angle between both profiles seen as
+// vectors of q-gram counts, between 0 and 1. Two empty profiles, which
+// only empty strings have, are identical.
+func (p QGramProfile) Cosine(o QGramProfile) float64 {
+	if len(p) == 0 && len(o) == 0 {
+		return 1
+	}
+	var dot, np, no float64
+	for gram, n := range p {
+		dot += float64(n * o[gram])
+		np += float64(n * n)
+	}
+	for _, n := range o {
+		no += float64(n * n)
+	}
+	if np == 0 || no == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(np*no)
+}
+
+// Distance returns Ukkonen's q-gram distance, the sum over all q-grams
+// of the difference of their counts in both profiles. For padded
+// profiles it is at most 2q times the Levenshtein distance, which makes
+// it a cheap filter: strings whose q-gram distance exceeds 2qk are more
+// than k edits apart.
+func (p QGramProfile) Distance(o QGramProfile) int {
+	d := 0
+	for gram, n := range p {
+		d += abs(n - o[gram])
+	}
+	for gram, n := range o {
+		if _, ok := p[gram]; !ok {
+			d += n
+		}
+	}
+	return d
+}
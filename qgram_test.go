@@ -0,0 +1,99 @@
+package twine
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+var qgramProfileTests = []struct {
+	in      string
+	q       int
+	pad     bool
+	profile QGramProfile
+}{
+	{"", 2, false, QGramProfile{}},
+	{"a", 2, false, QGramProfile{"a": 1}},
+	{"ab", 3, false, QGramProfile{"ab": 1}},
+	{"abab", 2, false, QGramProfile{"ab": 2, "ba": 1}},
+	{"abab", 0, false, QGramProfile{"a": 2, "b": 2}},
+	{"ab", 2, true, QGramProfile{"\x02a": 1, "ab": 1, "b\x03": 1}},
+	{"a", 3, true, QGramProfile{"\x02\x02a": 1, "\x02a\x03": 1, "a\x03\x03": 1}},
+	{"", 2, true, QGramProfile{"\x02\x03": 1}},
+	// unicode
+	{"üßü", 2, false, QGramProfile{"üß": 1, "ßü": 1}},
+}
+
+func TestQGramProfile(t *testing.T) {
+	for _, tt := range qgramProfileTests {
+		res := QGram{Q: tt.q, Pad: tt.pad}.Profile(tt.in)
+		if !reflect.DeepEqual(res, tt.profile) {
+			t.Errorf("QGram{%d, %v}.Profile(%s) => %q, want %q", tt.q, tt.pad, tt.in, res, tt.profile)
+		}
+	}
+}
+
+var qgramTests = []struct {
+	source   string
+	target   string
+	q        int
+	pad      bool
+	jaccard  float64
+	dice     float64
+	cosine   float64
+	distance int
+}{
+	{"", "", 2, false, 1, 1, 1, 0},
+	{"night", "night", 2, false, 1, 1, 1, 0},
+	{"night", "nacht", 2, false, 1.0 / 7, 0.25, 0.25, 6},
+	{"night", "nacht", 2, true, 1.0 / 3, 0.5, 0.5, 6},
+	{"abc", "xyz", 2, false, 0, 0, 0, 4},
+	{"abab", "ab", 2, false, 0.5, 2.0 / 3, 2 / math.Sqrt(5), 2},
+	{"a", "", 2, false, 0, 0, 0, 1},
+	// strings shorter than q only match themselves
+	{"a", "b", 2, false, 0, 0, 0, 2},
+	{"x", "y", 3, false, 0, 0, 0, 2},
+	{"ab", "ab", 3, false, 1, 1, 1, 0},
+	{"a", "", 2, true, 0, 0, 0, 3},
+}
+
+func TestQGram(t *testing.T) {
+	for _, tt := range qgramTests {
+		g := QGram{Q: tt.q, Pad: tt.pad}
+		if res := g.Jaccard(tt.source, tt.target); math.Abs(res-tt.jaccard) > 1e-9 {
+			t.Errorf("QGram{%d, %v}.Jaccard(%s, %s) => %v, want %v", tt.q, tt.pad, tt.source, tt.target, res, tt.jaccard)
+		}
+		if res := g.Dice(tt.source, tt.target); math.Abs(res-tt.dice) > 1e-9 {
+			t.Errorf("QGram{%d, %v}.Dice(%s, %s) => %v, want %v", tt.q, tt.pad, tt.source, tt.target, res, tt.dice)
+		}
+		if res := g.Cosine(tt.source, tt.target); math.Abs(res-tt.cosine) > 1e-9 {
+			t.Errorf("QGram{%d, %v}.Cosine(%s, %s) => %v, want %v", tt.q, tt.pad, tt.source, tt.target, res, tt.cosine)
+		}
+		if res := g.Distance(tt.source, tt.target); res != tt.distance {
+			t.Errorf("QGram{%d, %v}.Distance(%s, %s) => %d, want %d", tt.q, tt.pad, tt.source, tt.target, res, tt.distance)
+		}
+	}
+}
+
+// TestQGramDistanceBound checks the filtering bound between the q-gram
+// and Levenshtein distances on random strings.
+func TestQGramDistanceBound(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	word := func() string {
+		r := make([]rune, rnd.Intn(10))
+		for i := range r {
+			r[i] = []rune("abcß")[rnd.Intn(4)]
+		}
+		return string(r)
+	}
+	for _, q := range []int{1, 2, 3} {
+		g := QGram{Q: q, Pad: true}
+		for i := 0; i < 1000; i++ {
+			a, b := word(), word()
+			if d, lev := g.Distance(a, b), LevenshteinDistance(a, b); d > 2*q*lev {
+				t.Fatalf("QGram{%d, true}.Distance(%s, %s) => %d, levenshtein %d", q, a, b, d, lev)
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package twine
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// tokenWords splits text into lowercased words the way MumboJumbo reads
+// its corpus with the default tokenizer and normalizer.
+func tokenWords(text string) []string {
+	words := []string{}
+	for _, tok := range Tokenize(text) {
+		words = append(words, strings.ToLower(tok.Text))
+	}
+	return words
+}
+
+// TokenSortRatio returns the LevenshteinSimilarity of two strings after
+// splitting them into lowercased words, sorting the words and joining
+// them with spaces, so word order and punctuation are ignored, e.g.
+// "Smith, John" and "john smith" are identical.
+func TokenSortRatio(source string, target string) float64 {
+	s1 := tokenWords(source)
+	s2 := tokenWords(target)
+	sort.Strings(s1)
+	sort.Strings(s2)
+	return LevenshteinSimilarity(strings.Join(s1, " "), strings.Join(s2, " "))
+}
+
+// TokenSetRatio compares the sorted words the two strings share with
+// each string's sorted words and returns the best LevenshteinSimilarity,
+// so repeated words and words only one of them has count for less, e.g.
+// "John Smith" and "Smith, John A." score 1.
+func TokenSetRatio(source string, target string) float64 {
+	set1 := map[string]struct{}{}
+	for _, word := range tokenWords(source) {
+		set1[word] = struct{}{}
+	}
+	set2 := map[string]struct{}{}
+	for _, word := range tokenWords(target) {
+		set2[word] = struct{}{}
+	}
+
+	common, diff1, diff2 := []string{}, []string{}, []string{}
+	for word := range set1 {
+		if _, ok := set2[word]; ok {
+			common = append(common, word)
+		} else {
+			diff1 = append(diff1, word)
+		}
+	}
+	for word := range set2 {
+		if _, ok := set1[word]; !ok {
+			diff2 = append(diff2, word)
+		}
+	}
+	sort.Strings(common)
+	sort.Strings(diff1)
+	sort.Strings(diff2)
+
+	t0 := strings.Join(common, " ")
+	t1 := strings.TrimSpace(t0 + " " + strings.Join(diff1, " "))
+	t2 := strings.TrimSpace(t0 + " " + strings.Join(diff2, " "))
+	if len(common) == 0 {
+		return LevenshteinSimilarity(t1, t2)
+	}
+	return math.Max(LevenshteinSimilarity(t1, t2), math.Max(
+		LevenshteinSimilarity(t0, t1), LevenshteinSimilarity(t0, t2)))
+}
+
+// PartialRatio returns the best LevenshteinSimilarity between the
+// shorter of two strings and the substrings of the longer of the same
+// length, after both are split into lowercased words joined with
+// spaces, so "Main St" matches "12 Main St, Springfield" perfectly.
+func PartialRatio(source string, target string) float64 {
+	shorter := []rune(strings.Join(tokenWords(source), " "))
+	longer := []rune(strings.Join(tokenWords(target), " "))
+	if len(shorter) > len(longer) {
+		shorter, longer = longer, shorter
+	}
+	switch {
+	case len(longer) == 0:
+		return 1
+	case len(shorter) == 0:
+		return 0
+	}
+
+	s := string(shorter)
+	best := 0.0
+	for i := 0; i+len(shorter) <= len(longer) && best < 1; i++ {
+		if sim := LevenshteinSimilarity(s, string(longer[i:i+len(shorter)])); sim > best {
+			best = sim
+		}
+	}
+	return best
+}
@@ -0,0 +1,71 @@
+package twine
+
+import (
+	"math"
+	"testing"
+)
+
+var tokenSortRatioTests = []struct {
+	source string
+	target string
+	ratio  float64
+}{
+	{"", "", 1},
+	{"Smith, John", "john smith", 1},
+	{"new york mets", "Mets, New York!", 1},
+	{"Smith, John A.", "john smith", 10.0 / 12},
+	{"abc", "xyz", 0},
+}
+
+func TestTokenSortRatio(t *testing.T) {
+	for _, tt := range tokenSortRatioTests {
+		res := TokenSortRatio(tt.source, tt.target)
+		if math.Abs(res-tt.ratio) > 1e-9 {
+			t.Errorf("TokenSortRatio(%s, %s) => %v, want %v", tt.source, tt.target, res, tt.ratio)
+		}
+	}
+}
+
+var tokenSetRatioTests = []struct {
+	source string
+	target string
+	ratio  float64
+}{
+	{"", "", 1},
+	{"Smith, John A.", "john smith", 1},
+	{"john john smith", "Smith John", 1},
+	{"mariners vs angels", "los angeles angels vs mariners", 1},
+	{"abc", "xyz", 0},
+	{"jon smith", "john smyth", 8.0 / 10},
+}
+
+func TestTokenSetRatio(t *testing.T) {
+	for _, tt := range tokenSetRatioTests {
+		res := TokenSetRatio(tt.source, tt.target)
+		if math.Abs(res-tt.ratio) > 1e-9 {
+			t.Errorf("TokenSetRatio(%s, %s) => %v, want %v", tt.source, tt.target, res, tt.ratio)
+		}
+	}
+}
+
+var partialRatioTests = []struct {
+	source string
+	target string
+	ratio  float64
+}{
+	{"", "", 1},
+	{"", "abc", 0},
+	{"Main St", "12 Main St, Springfield", 1},
+	{"12 Main St, Springfield", "main st", 1},
+	{"mian st", "12 Main St", 5.0 / 7},
+	{"abc", "xyz", 0},
+}
+
+func TestPartialRatio(t *testing.T) {
+	for _, tt := range partialRatioTests {
+		res := PartialRatio(tt.source, tt.target)
+		if math.Abs(res-tt.ratio) > 1e-9 {
+			t.Errorf("PartialRatio(%s, %s) => %v, want %v", tt.source, tt.target, res, tt.ratio)
+		}
+	}
+}
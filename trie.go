@@ -1,7 +1,7 @@
 package twine
 
 import (
-	"fmt"
+	"sort"
 	"sync"
 )
 
@@ -18,8 +18,28 @@ type TrieNode struct {
 
 // Trie holds the root trie node
 type Trie struct {
-	Root *TrieNode
-	mu   *sync.Mutex
+	Root    *TrieNode
+	mu      *sync.Mutex
+	unicode Unicode
+}
+
+// TrieOption configures a Trie.
+type TrieOption func(*Trie)
+
+// WithTrieUnicode normalizes keys as u does before they are inserted,
+// looked up, deleted or searched, so keys differing only in their
+// normalization are the same key.
+func WithTrieUnicode(u Unicode) TrieOption {
+	return func(t *Trie) {
+		t.unicode = u
+	}
+}
+
+// TrieMatch is a key found by Search along with its values.
+type TrieMatch struct {
+	Key      string
+	Distance float64 // metric distance from the searched key
+	Values   []interface{}
 }
 
 // NewTrieNode initializes a node, as well
@@ -33,13 +53,18 @@ func NewTrieNode(key rune) *TrieNode {
 }
 
 // NewTrie initializes an empty root node.
-func NewTrie() *Trie {
-	return &Trie{Root: &TrieNode{children: map[rune]*TrieNode{}}, mu: &sync.Mutex{}}
+func NewTrie(opts ...TrieOption) *Trie {
+	t := &Trie{Root: &TrieNode{children: map[rune]*TrieNode{}}, mu: &sync.Mutex{}}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
 }
 
 // Insert updates the trie with key and appends a value in
 // the end node.
 func (t *Trie) Insert(key string, value interface{}) error {
+	key = t.unicode.Normalize(key)
 	it := t.Root
 	t.mu.Lock()
 	for _, runeChar := range []rune(key) {
@@ -60,8 +85,9 @@ func (t *Trie) Insert(key string, value interface{}) error {
 }
 
 // Get searches the trie and returns any values stored in the
-// end node or a not found error.
+// end node or ErrNotFound.
 func (t *Trie) Get(key string) ([]interface{}, error) {
+	key = t.unicode.Normalize(key)
 	it := t.Root
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -70,11 +96,11 @@ func (t *Trie) Get(key string) ([]interface{}, error) {
 		if found, ok := it.children[runeChar]; ok {
 			it = found
 		} else {
-			return nil, fmt.Errorf("not found")
+			return nil, ErrNotFound
 		}
 	}
 	if !it.isEnd {
-		return nil, fmt.Errorf("not found")
+		return nil, ErrNotFound
 	}
 	return it.values, nil
 }
@@ -82,6 +108,7 @@ func (t *Trie) Get(key string) ([]interface{}, error) {
 // Delete removes a given path within the trie by first finding
 // if it exists. It removes all values at the end node.
 func (t *Trie) Delete(key string) error {
+	key = t.unicode.Normalize(key)
 	// check if there first
 	_, err := t.Get(key)
 	if err != nil {
@@ -103,3 +130,36 @@ func (t *Trie) Delete(key string) error {
 	it.isEnd = false
 	return nil
 }
+
+// Search returns the keys within maxDistance of key as measured by
+// metric, closest first and then by key. A Unicode with Graphemes set can
+// be used as a grapheme aware metric.
+func (t *Trie) Search(key string, metric Metric, maxDistance float64) []TrieMatch {
+	key = t.unicode.Normalize(key)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	matches := []TrieMatch{}
+	var walk func(it *TrieNode, prefix []rune)
+	walk = func(it *TrieNode, prefix []rune) {
+		if it.isEnd {
+			word := string(prefix)
+			if d := metric.Distance(key, word); d <= maxDistance {
+				values := make([]interface{}, len(it.values))
+				copy(values, it.values)
+				matches = append(matches, TrieMatch{Key: word, Distance: d, Values: values})
+			}
+		}
+		for r, child := range it.children {
+			walk(child, append(prefix, r))
+		}
+	}
+	walk(t.Root, []rune{})
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Distance != matches[j].Distance {
+			return matches[i].Distance < matches[j].Distance
+		}
+		return matches[i].Key < matches[j].Key
+	})
+	return matches
+}
@@ -1,6 +1,10 @@
 package twine
 
-import "testing"
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
 
 func TestNewTrie(t *testing.T) {
 	tr := NewTrie()
@@ -39,7 +43,80 @@ func TestTrieDelete(t *testing.T) {
 		t.Fatal(err)
 	}
 	_, err = tr.Get("abc")
-	if err == nil {
-		t.Fatal("want empty")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get(abc) => %v, want %v", err, ErrNotFound)
+	}
+}
+
+var trieNotFoundTests = []string{"", "ab", "abd", "abcd", "xyz"}
+
+func TestTrieNotFound(t *testing.T) {
+	tr := NewTrie()
+	tr.Insert("abc", 1)
+	for _, key := range trieNotFoundTests {
+		if _, err := tr.Get(key); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(%s) => %v, want %v", key, err, ErrNotFound)
+		}
+		if err := tr.Delete(key); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Delete(%s) => %v, want %v", key, err, ErrNotFound)
+		}
+	}
+}
+
+var trieSearchTests = []struct {
+	key         string
+	maxDistance float64
+	out         []string
+}{
+	{"cat", 0, []string{"cat"}},
+	{"cat", 1, []string{"cat", "bat", "cart", "cut"}},
+	{"cst", 1, []string{"cat", "cut"}},
+	{"dog", 1, []string{}},
+}
+
+func TestTrieSearch(t *testing.T) {
+	tr := NewTrie()
+	for i, key := range []string{"cat", "cut", "cart", "bat", "catalog"} {
+		tr.Insert(key, i)
+	}
+	metric := DistanceMetric(LevenshteinDistance)
+	for _, tt := range trieSearchTests {
+		res := []string{}
+		for _, match := range tr.Search(tt.key, metric, tt.maxDistance) {
+			res = append(res, match.Key)
+		}
+		if !reflect.DeepEqual(res, tt.out) {
+			t.Errorf("Search(%s, %v) => %v, want %v", tt.key, tt.maxDistance, res, tt.out)
+		}
+	}
+}
+
+func TestTrieSearchMetric(t *testing.T) {
+	tr := NewTrie()
+	tr.Insert("cat", 1)
+	tr.Insert("cut", 2)
+	res := tr.Search("cst", QWERTY.CostModel(), 0.5)
+	if len(res) != 1 || res[0].Key != "cat" || res[0].Distance != 0.5 || res[0].Values[0] != 1 {
+		t.Errorf("Search(cst) => %+v, want cat", res)
+	}
+}
+
+func TestTrieUnicode(t *testing.T) {
+	tr := NewTrie(WithTrieUnicode(Unicode{Form: FormNFC}))
+	tr.Insert("cafe\u0301", 1)
+	tr.Insert("caf\u00e9", 2)
+	v, err := tr.Get("caf\u00e9")
+	if err != nil || !reflect.DeepEqual(v, []interface{}{1, 2}) {
+		t.Errorf("Get(café) => %v %v, want [1 2]", v, err)
+	}
+	res := tr.Search("cafe\u0301", DistanceMetric(LevenshteinDistance), 0)
+	if len(res) != 1 || res[0].Key != "caf\u00e9" {
+		t.Errorf("Search(café) => %+v, want café", res)
+	}
+	if err := tr.Delete("cafe\u0301"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tr.Get("caf\u00e9"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(café) => %v, want %v", err, ErrNotFound)
 	}
 }
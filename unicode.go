@@ -0,0 +1,86 @@
+package twine
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// UnicodeForm is a Unicode normalization form.
+type UnicodeForm int
+
+const (
+	// FormNone leaves strings as they are.
+	FormNone UnicodeForm = iota
+	// FormNFC composes characters, so an e followed by a combining acute
+	// accent becomes é.
+	FormNFC
+	// FormNFKC also replaces compatibility characters such as the ﬁ
+	// ligature or full width letters with their plain equivalent.
+	FormNFKC
+)
+
+// Unicode sets how strings are normalized and split into characters
+// before they are compared. The zero value compares raw runes.
+// http://unicode.org/reports/tr15/
+// http://unicode.org/reports/tr29/
+type Unicode struct {
+	Form UnicodeForm
+	// FoldDiacritics removes combining marks such as accents, so café
+	// becomes cafe. Letters that don't decompose such as ø or ß are
+	// kept. Folded strings are at least NFC normalized.
+	FoldDiacritics bool
+	// Graphemes compares extended grapheme clusters, the characters a
+	// reader perceives, rather than runes.
+	Graphemes bool
+}
+
+// Normalize returns s in the configured form, without diacritics when
+// they are folded.
+func (u Unicode) Normalize(s string) string {
+	if u.FoldDiacritics {
+		decompose, compose := norm.NFD, norm.NFC
+		if u.Form == FormNFKC {
+			decompose, compose = norm.NFKD, norm.NFKC
+		}
+		return compose.String(strings.Map(func(r rune) rune {
+			if unicode.Is(unicode.Mn, r) {
+				return -1
+			}
+			return r
+		}, decompose.String(s)))
+	}
+	switch u.Form {
+	case FormNFC:
+		return norm.NFC.String(s)
+	case FormNFKC:
+		return norm.NFKC.String(s)
+	}
+	return s
+}
+
+// LevenshteinDistance measures the Levenshtein distance between two
+// normalized strings, counting grapheme clusters when configured.
+func (u Unicode) LevenshteinDistance(source string, target string) int {
+	source, target = u.Normalize(source), u.Normalize(target)
+	if !u.Graphemes {
+		return LevenshteinDistance(source, target)
+	}
+	l := levenshteinPool.Get().(*Levenshtein)
+	d := l.GraphemeDistance(source, target)
+	levenshteinPool.Put(l)
+	return d
+}
+
+// Distance returns LevenshteinDistance so a Unicode can be used as a
+// Metric.
+func (u Unicode) Distance(source string, target string) float64 {
+	return float64(u.LevenshteinDistance(source, target))
+}
+
+// GraphemeLevenshteinDistance measures the Levenshtein distance between
+// two strings counting extended grapheme clusters rather than runes.
+func GraphemeLevenshteinDistance(source string, target string) int {
+	return Unicode{Graphemes: true}.LevenshteinDistance(source, target)
+}
@@ -0,0 +1,86 @@
+package twine
+
+import (
+	"strings"
+	"testing"
+)
+
+var normalizeTests = []struct {
+	unicode Unicode
+	in      string
+	out     string
+}{
+	{Unicode{}, "cafe\u0301", "cafe\u0301"},
+	{Unicode{Form: FormNFC}, "cafe\u0301", "caf\u00e9"},
+	{Unicode{Form: FormNFC}, "ﬁne", "ﬁne"},
+	{Unicode{Form: FormNFKC}, "ﬁne ＡＢＣ", "fine ABC"},
+	{Unicode{FoldDiacritics: true}, "Crème Brûlée", "Creme Brulee"},
+	{Unicode{FoldDiacritics: true}, "cafe\u0301 smørrebrød", "cafe smørrebrød"},
+	{Unicode{Form: FormNFKC, FoldDiacritics: true}, "ｃａｆé", "cafe"},
+}
+
+func TestUnicodeNormalize(t *testing.T) {
+	for _, tt := range normalizeTests {
+		res := tt.unicode.Normalize(tt.in)
+		if res != tt.out {
+			t.Errorf("%+v Normalize(%s) => %q, want %q", tt.unicode, tt.in, res, tt.out)
+		}
+	}
+}
+
+var graphemeTests = []struct {
+	source    string
+	target    string
+	runes     int
+	graphemes int
+}{
+	{"", "", 0, 0},
+	{"cafe\u0301", "caf", 2, 1},
+	{"cafe\u0301", "cafe", 1, 1},
+	{"👍🏽", "x", 2, 1},
+	{"👨‍👩‍👧", "", 5, 1},
+	{"🇫🇷", "🇩🇪", 2, 1},
+	{"🇫🇷x", "🇫🇷", 1, 1},
+	{"👍🏽👍", "👍👍🏽", 2, 2},
+	{strings.Repeat("👍🏽", 70), strings.Repeat("👍🏽", 69) + "👍", 1, 1},
+	{strings.Repeat("e\u0301", 70), strings.Repeat("e", 70), 70, 70},
+}
+
+func TestGraphemeLevenshteinDistance(t *testing.T) {
+	l := NewLevenshtein()
+	for _, tt := range graphemeTests {
+		if res := LevenshteinDistance(tt.source, tt.target); res != tt.runes {
+			t.Errorf("LevenshteinDistance(%s, %s) => %d, want %d", tt.source, tt.target, res, tt.runes)
+		}
+		if res := GraphemeLevenshteinDistance(tt.source, tt.target); res != tt.graphemes {
+			t.Errorf("GraphemeLevenshteinDistance(%s, %s) => %d, want %d", tt.source, tt.target, res, tt.graphemes)
+		}
+		if res := l.GraphemeDistance(tt.target, tt.source); res != tt.graphemes {
+			t.Errorf("GraphemeDistance(%s, %s) => %d, want %d", tt.target, tt.source, res, tt.graphemes)
+		}
+	}
+}
+
+var unicodeDistanceTests = []struct {
+	unicode  Unicode
+	source   string
+	target   string
+	distance int
+}{
+	{Unicode{}, "cafe\u0301", "caf\u00e9", 2},
+	{Unicode{Form: FormNFC}, "cafe\u0301", "caf\u00e9", 0},
+	{Unicode{Graphemes: true}, "cafe\u0301", "caf\u00e9", 1},
+	{Unicode{FoldDiacritics: true}, "cafe\u0301", "cafe", 0},
+	{Unicode{Form: FormNFKC, Graphemes: true}, "ﬁne", "fine👍🏽", 1},
+}
+
+func TestUnicodeLevenshteinDistance(t *testing.T) {
+	for _, tt := range unicodeDistanceTests {
+		if res := tt.unicode.LevenshteinDistance(tt.source, tt.target); res != tt.distance {
+			t.Errorf("%+v LevenshteinDistance(%s, %s) => %d, want %d", tt.unicode, tt.source, tt.target, res, tt.distance)
+		}
+		if res := tt.unicode.Distance(tt.source, tt.target); res != float64(tt.distance) {
+			t.Errorf("%+v Distance(%s, %s) => %v, want %d", tt.unicode, tt.source, tt.target, res, tt.distance)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package twine
+
+import "math"
+
+// CostModel holds the cost of each edit used by WeightedLevenshtein.
+type CostModel struct {
+	Insert     float64 // cost of inserting a character
+	Delete     float64 // cost of deleting a character
+	Substitute float64 // cost of replacing a character with another
+	// SubstituteFunc if set returns the cost of replacing a with b
+	// instead of Substitute. It is never called with a == b.
+	SubstituteFunc func(a, b rune) float64
+}
+
+// DefaultCostModel gives every edit a cost of 1 so WeightedLevenshtein
+// matches LevenshteinDistance.
+var DefaultCostModel = CostModel{Insert: 1, Delete: 1, Substitute: 1}
+
+// substitute returns the cost of replacing a with b.
+func (c CostModel) substitute(a, b rune) float64 {
+	switch {
+	case a == b:
+		return 0
+	case c.SubstituteFunc != nil:
+		return c.SubstituteFunc(a, b)
+	default:
+		return c.Substitute
+	}
+}
+
+// SubstitutionTable returns a SubstituteFunc for a CostModel that looks
+// up the cost of replacing a with b in costs, falling back to def for
+// pairs that are not listed. Pairs are symmetric, listing {'m', 'n'}
+// also sets the cost of replacing n with m.
+func SubstitutionTable(costs map[[2]rune]float64, def float64) func(a, b rune) float64 {
+	table := make(map[[2]rune]float64, 2*len(costs))
+	for pair, cost := range costs {
+		table[pair] = cost
+		table[[2]rune{pair[1], pair[0]}] = cost
+	}
+	return func(a, b rune) float64 {
+		if cost, ok := table[[2]rune{a, b}]; ok {
+			return cost
+		}
+		return def
+	}
+}
+
+// WeightedLevenshtein measures the Levenshtein distance between two
+// strings where each insertion, deletion and substitution costs what the
+// cost model says rather than 1.
+func WeightedLevenshtein(source string, target string, costs CostModel) float64 {
+	if source == target {
+		return 0
+	}
+	t1 := []rune(source)
+	t2 := []rune(target)
+
+	// v0 is the previous row of distances, the first row is the cost of
+	// inserting every character of target
+	v0 := make([]float64, len(t2)+1)
+	v1 := make([]float64, len(t2)+1)
+	for j := 1; j < len(v0); j++ {
+		v0[j] = v0[j-1] + costs.Insert
+	}
+
+	for i := 0; i < len(t1); i++ {
+		v1[0] = v0[0] + costs.Delete
+		for j := 0; j < len(t2); j++ {
+			v1[j+1] = math.Min(
+				math.Min(v1[j]+costs.Insert, v0[j+1]+costs.Delete),
+				v0[j]+costs.substitute(t1[i], t2[j]),
+			)
+		}
+		v0, v1 = v1, v0
+	}
+
+	return v0[len(t2)]
+}
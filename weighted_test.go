@@ -0,0 +1,57 @@
+package twine
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWeightedLevenshteinDefault(t *testing.T) {
+	for _, tt := range levTests {
+		res := WeightedLevenshtein(tt.source, tt.target, DefaultCostModel)
+		if res != float64(tt.distance) {
+			t.Errorf("WeightedLevenshtein(%s, %s) => %v, want %d", tt.source, tt.target, res, tt.distance)
+		}
+	}
+}
+
+// ocrCosts makes characters that OCR commonly confuses cheap to swap.
+var ocrCosts = CostModel{
+	Insert: 1,
+	Delete: 1,
+	SubstituteFunc: SubstitutionTable(map[[2]rune]float64{
+		{'m', 'n'}: 0.25,
+		{'0', 'o'}: 0.25,
+		{'1', 'l'}: 0.5,
+	}, 1),
+}
+
+var weightedLevenshteinTests = []struct {
+	source   string
+	target   string
+	costs    CostModel
+	distance float64
+}{
+	{"", "", ocrCosts, 0},
+	{"", "abc", ocrCosts, 3},
+	{"mouse", "nouse", ocrCosts, 0.25},
+	{"nouse", "mouse", ocrCosts, 0.25},
+	{"0pen", "open", ocrCosts, 0.25},
+	{"he110", "hello", ocrCosts, 1.25},
+	{"house", "mouse", ocrCosts, 1},
+	// insertions and deletions priced differently
+	{"ab", "abc", CostModel{Insert: 2, Delete: 0.5, Substitute: 1}, 2},
+	{"abc", "ab", CostModel{Insert: 2, Delete: 0.5, Substitute: 1}, 0.5},
+	// a substitution dearer than a deletion and insertion is never used
+	{"a", "b", CostModel{Insert: 1, Delete: 1, Substitute: 3}, 2},
+	// unicode
+	{"Schüßler", "Schübler", CostModel{Insert: 1, Delete: 1, Substitute: 0.5}, 0.5},
+}
+
+func TestWeightedLevenshtein(t *testing.T) {
+	for _, tt := range weightedLevenshteinTests {
+		res := WeightedLevenshtein(tt.source, tt.target, tt.costs)
+		if math.Abs(res-tt.distance) > 1e-9 {
+			t.Errorf("WeightedLevenshtein(%s, %s) => %v, want %v", tt.source, tt.target, res, tt.distance)
+		}
+	}
+}